@@ -0,0 +1,55 @@
+// Package cache provides a small TTL key/value cache abstraction, with
+// an in-memory LRU implementation and an optional Redis-backed second
+// tier, used to avoid re-querying ViaCEP/WeatherAPI for repeat lookups.
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Cache is a minimal TTL key/value store. Implementations must be safe
+// for concurrent use.
+type Cache interface {
+	// Get returns the cached value for key, or ok=false if it is
+	// missing or has expired.
+	Get(key string) (value string, ok bool)
+	// Set stores value under key for the given TTL.
+	Set(key string, value string, ttl time.Duration)
+}
+
+// Stats is a snapshot of hit/miss counts for a Counters cache.
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// Counters wraps a Cache and tracks hit/miss counts against it.
+type Counters struct {
+	Cache
+	hits   int64
+	misses int64
+}
+
+// NewCounters wraps next with hit/miss tracking.
+func NewCounters(next Cache) *Counters {
+	return &Counters{Cache: next}
+}
+
+func (c *Counters) Get(key string) (string, bool) {
+	value, ok := c.Cache.Get(key)
+	if ok {
+		atomic.AddInt64(&c.hits, 1)
+	} else {
+		atomic.AddInt64(&c.misses, 1)
+	}
+	return value, ok
+}
+
+// Stats returns a snapshot of the current hit/miss counts.
+func (c *Counters) Stats() Stats {
+	return Stats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUGetSet(t *testing.T) {
+	c := NewLRU(2)
+	c.Set("a", "1", time.Minute)
+
+	if value, ok := c.Get("a"); !ok || value != "1" {
+		t.Fatalf("got (%q, %v), want (1, true)", value, ok)
+	}
+
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("expected miss for unknown key")
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	c := NewLRU(2)
+	c.Set("a", "1", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected entry to have expired")
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU(2)
+	c.Set("a", "1", time.Minute)
+	c.Set("b", "2", time.Minute)
+	c.Get("a") // touch "a" so "b" becomes the least recently used
+	c.Set("c", "3", time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("expected \"b\" to have been evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected \"a\" to still be cached")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatal("expected \"c\" to be cached")
+	}
+}
+
+func TestCountersTracksHitsAndMisses(t *testing.T) {
+	counters := NewCounters(NewLRU(2))
+	counters.Set("a", "1", time.Minute)
+
+	counters.Get("a")
+	counters.Get("missing")
+
+	stats := counters.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("got %+v, want {Hits:1 Misses:1}", stats)
+	}
+}
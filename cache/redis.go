@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redis is a Cache backed by a Redis server, reached over a minimal
+// RESP client. The project has no external dependencies, so rather
+// than vendor a full client library this speaks just enough of the
+// protocol for GET/SET with a TTL.
+type Redis struct {
+	addr string
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewRedis creates a Redis-backed Cache talking to the server at addr
+// (host:port). The connection is established lazily on first use and
+// reopened automatically after an error.
+func NewRedis(addr string) *Redis {
+	return &Redis{addr: addr}
+}
+
+func (c *Redis) ensureConnLocked() error {
+	if c.conn != nil {
+		return nil
+	}
+	conn, err := net.DialTimeout("tcp", c.addr, 2*time.Second)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	c.r = bufio.NewReader(conn)
+	return nil
+}
+
+func (c *Redis) closeLocked() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+		c.r = nil
+	}
+}
+
+func (c *Redis) do(args ...string) (value string, found bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.ensureConnLocked(); err != nil {
+		return "", false, err
+	}
+
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(a), a)
+	}
+
+	if _, err := c.conn.Write([]byte(cmd.String())); err != nil {
+		c.closeLocked()
+		return "", false, err
+	}
+
+	value, found, err = c.readReply()
+	if err != nil {
+		c.closeLocked()
+		return "", false, err
+	}
+	return value, found, nil
+}
+
+// readReply parses a single RESP reply. Only the types GET/SET can
+// return are handled: simple strings, errors, integers, and bulk
+// strings.
+func (c *Redis) readReply() (string, bool, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", false, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", false, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], true, nil
+	case '-':
+		return "", false, fmt.Errorf("redis: %s", line[1:])
+	case '$':
+		n, convErr := strconv.Atoi(line[1:])
+		if convErr != nil {
+			return "", false, fmt.Errorf("redis: bad bulk length: %w", convErr)
+		}
+		if n < 0 {
+			return "", false, nil // nil bulk string, i.e. key not found
+		}
+		buf := make([]byte, n+2) // payload plus trailing CRLF
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return "", false, err
+		}
+		return string(buf[:n]), true, nil
+	default:
+		return "", false, fmt.Errorf("redis: unsupported reply type %q", line[0])
+	}
+}
+
+func (c *Redis) Get(key string) (string, bool) {
+	value, found, err := c.do("GET", key)
+	if err != nil {
+		return "", false
+	}
+	return value, found
+}
+
+func (c *Redis) Set(key, value string, ttl time.Duration) {
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		ms = 1
+	}
+	c.do("SET", key, value, "PX", strconv.FormatInt(ms, 10))
+}
@@ -0,0 +1,33 @@
+package cache
+
+import "time"
+
+// localRefillTTL bounds how long a value re-populated into the local
+// tier after a shared-tier hit stays there, so the local tier doesn't
+// serve a value long past what the original TTL intended.
+const localRefillTTL = 30 * time.Second
+
+// Tiered reads from a fast, process-local cache first and falls back
+// to a slower shared cache (e.g. Redis) on a miss, repopulating the
+// local tier so the next request for the same key is served locally.
+type Tiered struct {
+	Local  Cache
+	Shared Cache
+}
+
+func (t *Tiered) Get(key string) (string, bool) {
+	if value, ok := t.Local.Get(key); ok {
+		return value, true
+	}
+
+	value, ok := t.Shared.Get(key)
+	if ok {
+		t.Local.Set(key, value, localRefillTTL)
+	}
+	return value, ok
+}
+
+func (t *Tiered) Set(key, value string, ttl time.Duration) {
+	t.Local.Set(key, value, ttl)
+	t.Shared.Set(key, value, ttl)
+}
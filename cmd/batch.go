@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/observability"
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/providers"
+)
+
+const (
+	// maxBatchSize bounds how many CEPs a single /weather/batch or
+	// /weather/stream request may resolve, so one client can't
+	// monopolize the worker pool.
+	maxBatchSize = 100
+	// batchWorkers caps how many CEPs are resolved concurrently per
+	// request.
+	batchWorkers = 10
+)
+
+// BatchResult is one entry of a /weather/batch or /weather/stream
+// response: either Temperature is populated, or Error is, never both.
+type BatchResult struct {
+	CEP         string               `json:"cep"`
+	Temperature *TemperatureResponse `json:"temperature,omitempty"`
+	Error       string               `json:"error,omitempty"`
+}
+
+// newBatchHandler builds the handler for POST /weather/batch: it takes
+// a JSON array of CEPs, resolves them concurrently through a bounded
+// worker pool, and returns one BatchResult per CEP in request order.
+func newBatchHandler(geo providers.GeocodingProvider, weather providers.WeatherProvider, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		units, err := parseUnits(r.URL.Query().Get("units"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": err.Error()})
+			return
+		}
+
+		var ceps []string
+		if err := json.NewDecoder(r.Body).Decode(&ceps); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": "invalid request body: expected a JSON array of CEPs"})
+			return
+		}
+		if msg, ok := validateBatchSize(ceps); !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": msg})
+			return
+		}
+
+		results := resolveBatch(r.Context(), geo, weather, ceps, units, logger)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(results)
+	}
+}
+
+// newStreamHandler builds the handler for GET /weather/stream: it
+// resolves every ?cep= in the query string concurrently, same as
+// /weather/batch, but streams each BatchResult to the client as a
+// Server-Sent Event as soon as it resolves instead of waiting for the
+// whole batch.
+func newStreamHandler(geo providers.GeocodingProvider, weather providers.WeatherProvider, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		units, err := parseUnits(r.URL.Query().Get("units"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": err.Error()})
+			return
+		}
+
+		ceps := r.URL.Query()["cep"]
+		if msg, ok := validateBatchSize(ceps); !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": msg})
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		for result := range resolveBatchStream(r.Context(), geo, weather, ceps, units, logger) {
+			payload, err := json.Marshal(result)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+		}
+	}
+}
+
+// validateBatchSize checks that ceps is non-empty and within
+// maxBatchSize, returning a client-facing message when it isn't.
+func validateBatchSize(ceps []string) (message string, ok bool) {
+	if len(ceps) == 0 {
+		return "at least one CEP is required", false
+	}
+	if len(ceps) > maxBatchSize {
+		return fmt.Sprintf("at most %d CEPs are allowed per request", maxBatchSize), false
+	}
+	return "", true
+}
+
+// resolveBatch resolves every CEP concurrently through a bounded
+// worker pool and returns the results in the same order as ceps.
+func resolveBatch(ctx context.Context, geo providers.GeocodingProvider, weather providers.WeatherProvider, ceps []string, units providers.Units, logger *slog.Logger) []BatchResult {
+	results := make([]BatchResult, len(ceps))
+	sem := make(chan struct{}, batchWorkers)
+	var wg sync.WaitGroup
+
+	for i, cep := range ceps {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, cep string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = resolveOne(ctx, geo, weather, cep, units, logger)
+		}(i, cep)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// resolveBatchStream mirrors resolveBatch, but returns a channel that
+// yields each BatchResult as soon as it resolves rather than waiting
+// for the whole batch. The channel is closed once every CEP has been
+// resolved.
+func resolveBatchStream(ctx context.Context, geo providers.GeocodingProvider, weather providers.WeatherProvider, ceps []string, units providers.Units, logger *slog.Logger) <-chan BatchResult {
+	out := make(chan BatchResult)
+	sem := make(chan struct{}, batchWorkers)
+	var wg sync.WaitGroup
+
+	for _, cep := range ceps {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(cep string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			out <- resolveOne(ctx, geo, weather, cep, units, logger)
+		}(cep)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// resolveOne resolves a single CEP to a BatchResult, reusing the same
+// geocode-then-weather flow as /weather.
+func resolveOne(ctx context.Context, geo providers.GeocodingProvider, weather providers.WeatherProvider, cep string, units providers.Units, logger *slog.Logger) BatchResult {
+	matched, _ := regexp.MatchString(`^\d{8}$`, cep)
+	if !matched {
+		return BatchResult{CEP: cep, Error: "invalid zipcode"}
+	}
+
+	ctx, endSpan := observability.StartSpan(ctx, logger, "batch_lookup")
+	var resultErr error
+	defer func() { endSpan(resultErr) }()
+
+	loc, err := geo.Geocode(ctx, cep)
+	if err != nil {
+		resultErr = err
+		_, message := providerStatus(err)
+		return BatchResult{CEP: cep, Error: message}
+	}
+
+	var cond providers.Conditions
+	if loc.HasCoordinates {
+		cond, err = weather.CurrentByCoordinates(ctx, loc.Lat, loc.Lon, units)
+	} else {
+		cond, err = weather.CurrentByCity(ctx, loc.City, units)
+	}
+	if err != nil {
+		resultErr = err
+		_, message := providerStatus(err)
+		return BatchResult{CEP: cep, Error: message}
+	}
+
+	temp := temperatureResponse(cond)
+	return BatchResult{CEP: cep, Temperature: &temp}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/providers"
+)
+
+// TestBatchPartialFailure garante que um CEP inválido entre vários
+// válidos não derruba o restante do batch: cada item carrega seu
+// próprio resultado ou erro.
+func TestBatchPartialFailure(t *testing.T) {
+	geo := providers.GeocodingChain{&providers.Mock{Cities: map[string]providers.Location{
+		"01001000": {City: "São Paulo"},
+	}}}
+	weather := providers.WeatherChain{&providers.Mock{Temps: map[string]float64{
+		"São Paulo": 25.0,
+	}}}
+	handler := newBatchHandler(geo, weather, testLogger())
+
+	body, err := json.Marshal([]string{"01001000", "99999999", "bad-cep"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/weather/batch", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v. Body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	var results []BatchResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &results); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	if results[0].Temperature == nil || results[0].Error != "" {
+		t.Errorf("expected a successful result for 01001000, got %+v", results[0])
+	}
+	if results[1].Temperature != nil || results[1].Error == "" {
+		t.Errorf("expected a not-found error for 99999999, got %+v", results[1])
+	}
+	if results[2].Temperature != nil || results[2].Error != "invalid zipcode" {
+		t.Errorf("expected an invalid zipcode error for bad-cep, got %+v", results[2])
+	}
+}
+
+// TestBatchValidation testa os limites de tamanho e o corpo malformado
+// de POST /weather/batch.
+func TestBatchValidation(t *testing.T) {
+	handler := newBatchHandler(providers.GeocodingChain{}, providers.WeatherChain{}, testLogger())
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "empty array", body: "[]"},
+		{name: "malformed body", body: "not json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/weather/batch", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusBadRequest {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+// TestBatchTooLarge garante que um batch acima de maxBatchSize é
+// rejeitado antes de qualquer lookup.
+func TestBatchTooLarge(t *testing.T) {
+	handler := newBatchHandler(providers.GeocodingChain{}, providers.WeatherChain{}, testLogger())
+
+	ceps := make([]string, maxBatchSize+1)
+	for i := range ceps {
+		ceps[i] = "01001000"
+	}
+	body, err := json.Marshal(ceps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/weather/batch", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+// TestStreamPartialFailure garante que /weather/stream emite um evento
+// SSE por CEP, incluindo os que falham, sem interromper os demais.
+func TestStreamPartialFailure(t *testing.T) {
+	geo := providers.GeocodingChain{&providers.Mock{Cities: map[string]providers.Location{
+		"01001000": {City: "São Paulo"},
+	}}}
+	weather := providers.WeatherChain{&providers.Mock{Temps: map[string]float64{
+		"São Paulo": 25.0,
+	}}}
+	handler := newStreamHandler(geo, weather, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/weather/stream?cep=01001000&cep=99999999", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v. Body: %s", status, http.StatusOK, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected Content-Type text/event-stream, got %q", ct)
+	}
+
+	var events []BatchResult
+	scanner := bufio.NewScanner(rr.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var result BatchResult
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &result); err != nil {
+			t.Fatalf("failed to unmarshal event: %v", err)
+		}
+		events = append(events, result)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %+v", len(events), events)
+	}
+
+	byCEP := make(map[string]BatchResult, len(events))
+	for _, e := range events {
+		byCEP[e.CEP] = e
+	}
+	if r := byCEP["01001000"]; r.Temperature == nil {
+		t.Errorf("expected a successful result for 01001000, got %+v", r)
+	}
+	if r := byCEP["99999999"]; r.Error == "" {
+		t.Errorf("expected a not-found error for 99999999, got %+v", r)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/observability"
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/providers"
+)
+
+const (
+	// defaultForecastDays is how many days GET /forecast returns when
+	// the caller doesn't specify `days`.
+	defaultForecastDays = 3
+	// maxForecastDays bounds how many days a single /forecast request
+	// may ask for.
+	maxForecastDays = 10
+)
+
+// ForecastResponse is the payload for GET /forecast: hourly and daily
+// points for the requested range, in provider order.
+type ForecastResponse struct {
+	Units  string        `json:"units,omitempty"`
+	Hourly []HourlyPoint `json:"hourly"`
+	Daily  []DailyPoint  `json:"daily"`
+}
+
+// HourlyPoint is one hour of forecast data.
+type HourlyPoint struct {
+	Time             string  `json:"time"`
+	TempC            float64 `json:"temp_C"`
+	TempF            float64 `json:"temp_F"`
+	TempK            float64 `json:"temp_K"`
+	PrecipitationPct float64 `json:"precipitation_pct"`
+	WindKPH          float64 `json:"wind_kph"`
+	Condition        string  `json:"condition"`
+	ConditionCode    int     `json:"condition_code"`
+}
+
+// DailyPoint is one day of forecast data.
+type DailyPoint struct {
+	Date             string  `json:"date"`
+	MinTempC         float64 `json:"min_temp_C"`
+	MinTempF         float64 `json:"min_temp_F"`
+	MinTempK         float64 `json:"min_temp_K"`
+	MaxTempC         float64 `json:"max_temp_C"`
+	MaxTempF         float64 `json:"max_temp_F"`
+	MaxTempK         float64 `json:"max_temp_K"`
+	PrecipitationPct float64 `json:"precipitation_pct"`
+	WindKPH          float64 `json:"wind_kph"`
+	Sunrise          string  `json:"sunrise,omitempty"`
+	Sunset           string  `json:"sunset,omitempty"`
+	Condition        string  `json:"condition"`
+	ConditionCode    int     `json:"condition_code"`
+}
+
+// newForecastHandler monta o handler de /forecast, aceitando tanto
+// ?cep=... (com geocoding via geo) quanto ?lat=...&lon=..., mais um
+// `days` opcional.
+func newForecastHandler(geo providers.GeocodingProvider, forecast providers.ForecastProvider, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query()
+
+		units, err := parseUnits(q.Get("units"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": err.Error()})
+			return
+		}
+
+		days, err := parseForecastDays(q.Get("days"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": err.Error()})
+			return
+		}
+
+		ctx, endSpan := observability.StartSpan(r.Context(), logger, "forecast_request")
+		var reqErr error
+		defer func() { endSpan(reqErr) }()
+
+		var f providers.Forecast
+		if q.Has("lat") && q.Has("lon") {
+			latF, err := strconv.ParseFloat(q.Get("lat"), 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"message": "invalid lat/lon"})
+				return
+			}
+			lonF, err := strconv.ParseFloat(q.Get("lon"), 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"message": "invalid lat/lon"})
+				return
+			}
+
+			f, reqErr = forecast.ForecastByCoordinates(ctx, latF, lonF, days, units)
+			if reqErr != nil {
+				writeProviderError(w, reqErr)
+				return
+			}
+		} else {
+			cep := q.Get("cep")
+			if cep == "" {
+				http.Error(w, "CEP parameter is required", http.StatusBadRequest)
+				return
+			}
+			matched, _ := regexp.MatchString(`^\d{8}$`, cep)
+			if !matched {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(w).Encode(map[string]string{"message": "invalid zipcode"})
+				return
+			}
+
+			loc, err := geo.Geocode(ctx, cep)
+			if err != nil {
+				reqErr = err
+				writeProviderError(w, err)
+				return
+			}
+
+			if loc.HasCoordinates {
+				f, reqErr = forecast.ForecastByCoordinates(ctx, loc.Lat, loc.Lon, days, units)
+			} else {
+				f, reqErr = forecast.ForecastByCity(ctx, loc.City, days, units)
+			}
+			if reqErr != nil {
+				writeProviderError(w, reqErr)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(forecastResponse(f))
+	}
+}
+
+// parseForecastDays validates the `days` query parameter, defaulting
+// to defaultForecastDays when absent and rejecting anything outside
+// [1, maxForecastDays].
+func parseForecastDays(raw string) (int, error) {
+	if raw == "" {
+		return defaultForecastDays, nil
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 1 || days > maxForecastDays {
+		return 0, fmt.Errorf("invalid days: must be an integer between 1 and %d", maxForecastDays)
+	}
+	return days, nil
+}
+
+// forecastResponse converts a providers.Forecast into the wire format
+// returned by /forecast, converting temperatures across C/F/K the same
+// way /weather does and resolving each point's Condition to its stable
+// code and human-readable label.
+func forecastResponse(f providers.Forecast) ForecastResponse {
+	resp := ForecastResponse{Units: string(f.Units)}
+
+	for _, h := range f.Hourly {
+		resp.Hourly = append(resp.Hourly, HourlyPoint{
+			Time:             h.Time.Format(providers.DateFormat + " 15:04"),
+			TempC:            h.TempC,
+			TempF:            h.TempC*1.8 + 32,
+			TempK:            h.TempC + 273.15,
+			PrecipitationPct: h.PrecipitationPct,
+			WindKPH:          h.WindKPH,
+			Condition:        h.Condition.Label(),
+			ConditionCode:    int(h.Condition),
+		})
+	}
+
+	for _, d := range f.Daily {
+		point := DailyPoint{
+			Date:             d.Date.Format(providers.DateFormat),
+			MinTempC:         d.MinTempC,
+			MinTempF:         d.MinTempC*1.8 + 32,
+			MinTempK:         d.MinTempC + 273.15,
+			MaxTempC:         d.MaxTempC,
+			MaxTempF:         d.MaxTempC*1.8 + 32,
+			MaxTempK:         d.MaxTempC + 273.15,
+			PrecipitationPct: d.PrecipitationPct,
+			WindKPH:          d.WindKPH,
+			Condition:        d.Condition.Label(),
+			ConditionCode:    int(d.Condition),
+		}
+		if !d.Sunrise.IsZero() {
+			point.Sunrise = d.Sunrise.Format("15:04")
+		}
+		if !d.Sunset.IsZero() {
+			point.Sunset = d.Sunset.Format("15:04")
+		}
+		resp.Daily = append(resp.Daily, point)
+	}
+
+	return resp
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/providers"
+)
+
+// TestForecastDaysValidation garante que `days` aceita apenas um
+// inteiro dentro de [1, maxForecastDays].
+func TestForecastDaysValidation(t *testing.T) {
+	handler := newForecastHandler(providers.GeocodingChain{}, providers.ForecastChain{}, testLogger())
+
+	tests := []struct {
+		name string
+		days string
+	}{
+		{name: "non-numeric", days: "bogus"},
+		{name: "zero", days: "0"},
+		{name: "above max", days: "999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/forecast?cep=01001000&days="+tt.days, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusBadRequest {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+// TestForecastNotFound simula um CEP que nenhum GeocodingProvider
+// consegue resolver.
+func TestForecastNotFound(t *testing.T) {
+	geo := providers.GeocodingChain{&providers.Mock{Cities: map[string]providers.Location{}}}
+	handler := newForecastHandler(geo, providers.ForecastChain{&providers.Mock{}}, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/forecast?cep=99999999", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+// TestForecastSuccessScenario testa o caminho feliz, verificando as
+// conversões C/F/K e o rótulo de condição resolvido a partir do código
+// estável do repositório.
+func TestForecastSuccessScenario(t *testing.T) {
+	day, err := time.Parse(providers.DateFormat, "2026-07-27")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	geo := providers.GeocodingChain{&providers.Mock{Cities: map[string]providers.Location{
+		"01001000": {City: "São Paulo"},
+	}}}
+	forecast := providers.ForecastChain{&providers.Mock{Forecasts: map[string]providers.Forecast{
+		"São Paulo": {
+			Daily: []providers.DailyForecast{
+				{Date: day, MinTempC: 15, MaxTempC: 25, Condition: providers.ConditionRain},
+			},
+		},
+	}}}
+	handler := newForecastHandler(geo, forecast, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/forecast?cep=01001000&days=1", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v. Body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	var resp ForecastResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Daily) != 1 {
+		t.Fatalf("expected 1 daily point, got %d", len(resp.Daily))
+	}
+
+	daily := resp.Daily[0]
+	if daily.MaxTempF != daily.MaxTempC*1.8+32 {
+		t.Errorf("MaxTempF conversion incorrect: got %f, expected %f", daily.MaxTempF, daily.MaxTempC*1.8+32)
+	}
+	if daily.MinTempK != daily.MinTempC+273.15 {
+		t.Errorf("MinTempK conversion incorrect: got %f, expected %f", daily.MinTempK, daily.MinTempC+273.15)
+	}
+	if daily.Condition != "rain" {
+		t.Errorf("expected condition %q, got %q", "rain", daily.Condition)
+	}
+	if daily.ConditionCode != int(providers.ConditionRain) {
+		t.Errorf("expected condition code %d, got %d", providers.ConditionRain, daily.ConditionCode)
+	}
+}
@@ -1,175 +1,345 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"net/url"
 	"os"
 	"regexp"
-)
+	"strconv"
+	"time"
 
-// ViaCEPResponse representa a estrutura da resposta da API ViaCEP
-type ViaCEPResponse struct {
-	Localidade string `json:"localidade"`
-	Erro       string `json:"erro"`
-}
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/cache"
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/httpclient"
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/observability"
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/providers"
+)
 
-// WeatherAPIResponse representa a estrutura da resposta da API WeatherAPI
-type WeatherAPIResponse struct {
-	Current struct {
-		TempC float64 `json:"temp_c"`
-	} `json:"current"`
-	Error struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"error"`
-}
+const (
+	// cepCacheTTL is long because a CEP's city rarely, if ever, changes.
+	cepCacheTTL = 30 * 24 * time.Hour
+	// weatherCacheTTL is short because conditions change minute to minute.
+	weatherCacheTTL = 5 * time.Minute
+	// defaultCacheSize is the default LRU capacity when CACHE_SIZE isn't set.
+	defaultCacheSize = 1000
+)
 
-// TemperatureResponse representa a estrutura da resposta final do nosso serviço
+// TemperatureResponse representa a estrutura da resposta final do nosso
+// serviço. Temp is expressed in whatever Units was requested (metric,
+// imperial, or standard/Kelvin).
 type TemperatureResponse struct {
-	TempC float64 `json:"temp_C"`
-	TempF float64 `json:"temp_F"`
-	TempK float64 `json:"temp_K"`
+	Temp  float64 `json:"temp"`
+	Units string  `json:"units,omitempty"`
 }
 
-// handleGetWeather lida com as requisições HTTP para obter o clima
-func handleGetWeather(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+// newWeatherHandler monta o handler de /weather, injetando as cadeias de
+// geocoding e de clima para que o failover entre providers fique fora da
+// camada HTTP. logger receives one "weather_request" span per call,
+// plus nested spans around the geocoding and weather lookups.
+func newWeatherHandler(geo providers.GeocodingProvider, weather providers.WeatherProvider, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		tc := observability.ParseTraceParent(r.Header.Get("traceparent"))
+		ctx := observability.WithTraceContext(r.Context(), tc)
+		ctx, endSpan := observability.StartSpan(ctx, logger, "weather_request")
+		var reqErr error
+		defer func() { endSpan(reqErr) }()
+
+		q := r.URL.Query()
+
+		units, err := parseUnits(q.Get("units"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": err.Error()})
+			return
+		}
+
+		if q.Has("lat") && q.Has("lon") {
+			reqErr = handleCoordinates(ctx, w, weather, q.Get("lat"), q.Get("lon"), units, logger)
+			return
+		}
+
+		cep := q.Get("cep")
+		if cep == "" {
+			http.Error(w, "CEP parameter is required", http.StatusBadRequest)
+			return
+		}
 
-	cep := r.URL.Query().Get("cep")
-	if cep == "" {
-		http.Error(w, "CEP parameter is required", http.StatusBadRequest)
-		return
+		// Validação do formato do CEP (8 dígitos numéricos)
+		matched, _ := regexp.MatchString(`^\d{8}$`, cep)
+		if !matched {
+			w.WriteHeader(http.StatusUnprocessableEntity) // 422
+			json.NewEncoder(w).Encode(map[string]string{"message": "invalid zipcode"})
+			return
+		}
+
+		geoCtx, endGeoSpan := observability.StartSpan(ctx, logger, "geocode")
+		loc, err := geo.Geocode(geoCtx, cep)
+		endGeoSpan(err)
+		if err != nil {
+			reqErr = err
+			writeProviderError(w, err)
+			return
+		}
+		logger.Info("cep resolved", slog.String("cep", cep), slog.String("city", loc.City))
+
+		weatherCtx, endWeatherSpan := observability.StartSpan(ctx, logger, "weather_lookup")
+		var cond providers.Conditions
+		if loc.HasCoordinates {
+			cond, err = weather.CurrentByCoordinates(weatherCtx, loc.Lat, loc.Lon, units)
+		} else {
+			cond, err = weather.CurrentByCity(weatherCtx, loc.City, units)
+		}
+		endWeatherSpan(err)
+		if err != nil {
+			reqErr = err
+			writeProviderError(w, err)
+			return
+		}
+
+		writeTemperature(w, cond)
 	}
+}
 
-	// Validação do formato do CEP (8 dígitos numéricos)
-	matched, _ := regexp.MatchString(`^\d{8}$`, cep)
-	if !matched {
-		w.WriteHeader(http.StatusUnprocessableEntity) // 422
-		json.NewEncoder(w).Encode(map[string]string{"message": "invalid zipcode"})
-		return
+// handleCoordinates atende o caso em que o cliente já informa lat/lon,
+// dispensando a etapa de geocoding. It returns the provider error (if
+// any) so the caller's span can record it; invalid-input errors are not
+// returned since they never reach a provider.
+func handleCoordinates(ctx context.Context, w http.ResponseWriter, weather providers.WeatherProvider, lat, lon string, units providers.Units, logger *slog.Logger) error {
+	latF, err := strconv.ParseFloat(lat, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"message": "invalid lat/lon"})
+		return nil
+	}
+	lonF, err := strconv.ParseFloat(lon, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"message": "invalid lat/lon"})
+		return nil
 	}
 
-	// 1. Consultar ViaCEP para obter a cidade
-	viaCEPURL := fmt.Sprintf("https://viacep.com.br/ws/%s/json/", cep)
-	log.Printf("Consultando ViaCEP: %s", viaCEPURL)
-	viaCEPResp, err := http.Get(viaCEPURL)
+	ctx, endSpan := observability.StartSpan(ctx, logger, "weather_lookup")
+	cond, err := weather.CurrentByCoordinates(ctx, latF, lonF, units)
+	endSpan(err)
 	if err != nil {
-		log.Printf("Erro ao consultar ViaCEP: %v", err)
-		http.Error(w, "Failed to get city information", http.StatusInternalServerError)
-		return
+		writeProviderError(w, err)
+		return err
 	}
-	defer viaCEPResp.Body.Close()
 
-	if viaCEPResp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(viaCEPResp.Body)
-		log.Printf("ViaCEP retornou status %d para CEP %s. Corpo: %s", viaCEPResp.StatusCode, cep, string(bodyBytes))
-		http.Error(w, "Failed to get city information from ViaCEP (non-200 status)", http.StatusInternalServerError)
-		return
+	writeTemperature(w, cond)
+	return nil
+}
+
+// parseUnits valida o parâmetro `units`, aceitando metric, imperial,
+// standard ou vazio (equivalente a metric).
+func parseUnits(raw string) (providers.Units, error) {
+	switch providers.Units(raw) {
+	case "":
+		return providers.UnitsMetric, nil
+	case providers.UnitsMetric, providers.UnitsImperial, providers.UnitsStandard:
+		return providers.Units(raw), nil
+	default:
+		return "", fmt.Errorf("invalid units: must be one of metric, imperial, standard")
 	}
+}
 
-	bodyBytes, err := io.ReadAll(viaCEPResp.Body)
-	if err != nil {
-		log.Printf("Erro ao ler corpo da resposta do ViaCEP para CEP %s: %v", cep, err)
-		http.Error(w, "Failed to read city information response", http.StatusInternalServerError)
-		return
+// temperatureResponse converts provider conditions into the response
+// shape returned by /weather and /weather/batch alike, expressing Temp
+// in the Units that was actually requested.
+func temperatureResponse(cond providers.Conditions) TemperatureResponse {
+	return TemperatureResponse{
+		Temp:  cond.Units.ConvertFromCelsius(cond.TempC),
+		Units: string(cond.Units),
+	}
+}
+
+func writeTemperature(w http.ResponseWriter, cond providers.Conditions) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // 200
+	json.NewEncoder(w).Encode(temperatureResponse(cond))
+}
+
+// providerStatus traduz os erros sentinela do pacote providers para os
+// códigos HTTP e mensagens já usados pelo serviço: 404 quando a
+// localização não é encontrada, 503 quando o erro é ErrUnavailable
+// (e.g. o último provider da cadeia estava indisponível), e 500 caso
+// contrário. Shared by /weather, /weather/batch, /weather/stream, and
+// /forecast so the four endpoints agree on error reporting.
+func providerStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, providers.ErrNotFound):
+		return http.StatusNotFound, "can not find zipcode"
+	case errors.Is(err, providers.ErrUnavailable):
+		return http.StatusServiceUnavailable, "weather service temporarily unavailable"
+	default:
+		return http.StatusInternalServerError, "Failed to get weather information"
 	}
+}
+
+// writeProviderError writes a provider error as a JSON error body with
+// the matching HTTP status. The error itself is logged by the caller's
+// span, not here.
+func writeProviderError(w http.ResponseWriter, err error) {
+	status, message := providerStatus(err)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// newProviderChains monta as cadeias padrão de geocoding, clima atual,
+// e previsão usadas em produção: ViaCEP com fallback para Nominatim, e
+// WeatherAPI com fallback para OpenWeatherMap para o clima atual.
+// Previsão usa só o WeatherAPI, já que a API de forecast do
+// OpenWeatherMap exige um plano pago separado.
+func newProviderChains() (providers.GeocodingChain, providers.WeatherChain, providers.ForecastChain) {
+	httpClient := httpclient.New(httpclient.DefaultConfig())
 
-	var viaCEPData ViaCEPResponse
-	if err := json.NewDecoder(bytes.NewBuffer(bodyBytes)).Decode(&viaCEPData); err != nil {
-		log.Printf("Erro ao decodificar resposta do ViaCEP para CEP %s: %v. Corpo recebido: %s", cep, err, string(bodyBytes))
-		http.Error(w, "Failed to parse city information", http.StatusInternalServerError)
-		return
+	geo := providers.GeocodingChain{
+		&providers.ViaCEP{Client: httpClient},
+		&providers.Nominatim{Client: httpClient},
 	}
 
-	// O ViaCEP pode retornar "true" (string) ou true (bool), então verificamos ambos
-	if viaCEPData.Erro == "true" || viaCEPData.Erro == "1" || viaCEPData.Localidade == "" {
-		log.Printf("CEP %s não encontrado. ViaCEP retornou erro: %s, localidade: %s", cep, viaCEPData.Erro, viaCEPData.Localidade)
-		w.WriteHeader(http.StatusNotFound) // 404
-		json.NewEncoder(w).Encode(map[string]string{"message": "can not find zipcode"})
-		return
+	weatherAPI := &providers.WeatherAPI{Client: httpClient, APIKey: os.Getenv("WEATHER_API_KEY")}
+
+	weather := providers.WeatherChain{
+		weatherAPI,
+		&providers.OpenWeatherMap{Client: httpClient, APIKey: os.Getenv("OPENWEATHERMAP_API_KEY")},
 	}
 
-	cityName := viaCEPData.Localidade
-	log.Printf("CEP %s encontrado. Cidade: %s", cep, cityName)
+	forecast := providers.ForecastChain{weatherAPI}
+
+	return geo, weather, forecast
+}
 
-	// 2. Consultar WeatherAPI para obter a temperatura
-	weatherAPIKey := os.Getenv("WEATHER_API_KEY")
-	if weatherAPIKey == "" {
-		log.Print("WEATHER_API_KEY não definida. Por favor, defina a variável de ambiente.")
-		http.Error(w, "Weather API key not configured", http.StatusInternalServerError)
-		return
+// newCache builds the local LRU tier used for a lookup kind, adding a
+// Redis tier on top when REDIS_ADDR is set so multiple instances share
+// a cache instead of each keeping their own.
+func newCache() *cache.Counters {
+	local := cache.NewLRU(cacheSize())
+
+	var backing cache.Cache = local
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		backing = &cache.Tiered{Local: local, Shared: cache.NewRedis(addr)}
 	}
 
-	encodedCityName := url.QueryEscape(cityName)
-	weatherAPIURL := fmt.Sprintf("https://api.weatherapi.com/v1/current.json?key=%s&q=%s", weatherAPIKey, encodedCityName)
-	log.Printf("Consultando WeatherAPI para %s, URL: %s", cityName, weatherAPIURL)
+	return cache.NewCounters(backing)
+}
 
-	weatherResp, err := http.Get(weatherAPIURL)
-	if err != nil {
-		log.Printf("Erro ao consultar WeatherAPI: %v", err)
-		http.Error(w, "Failed to get weather information", http.StatusInternalServerError)
-		return
-	}
-	defer weatherResp.Body.Close()
-
-	if weatherResp.StatusCode != http.StatusOK {
-		// Tenta ler o corpo da resposta para ver a mensagem de erro da WeatherAPI
-		bodyBytes, _ := io.ReadAll(weatherResp.Body)
-		log.Printf("WeatherAPI retornou status %d. Corpo: %s", weatherResp.StatusCode, string(bodyBytes))
-
-		var weatherError WeatherAPIResponse
-		if err := json.Unmarshal(bodyBytes, &weatherError); err == nil && weatherError.Error.Code == 1006 {
-			// Se a WeatherAPI não encontrar a localização, podemos considerar como CEP não encontrado para o usuário
-			w.WriteHeader(http.StatusNotFound) // 404
-			json.NewEncoder(w).Encode(map[string]string{"message": "can not find zipcode"})
-			return
-		}
+func cacheSize() int {
+	raw := os.Getenv("CACHE_SIZE")
+	if raw == "" {
+		return defaultCacheSize
+	}
+	size, err := strconv.Atoi(raw)
+	if err != nil || size <= 0 {
+		return defaultCacheSize
+	}
+	return size
+}
 
-		http.Error(w, "Failed to get weather information from WeatherAPI", http.StatusInternalServerError)
-		return
+// newCacheStatsHandler exposes cache hit/miss counters for operators,
+// ahead of proper metrics scraping.
+func newCacheStatsHandler(geoCache, weatherCache *cache.Counters) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]cache.Stats{
+			"cep_cache":     geoCache.Stats(),
+			"weather_cache": weatherCache.Stats(),
+		})
 	}
+}
 
-	var weatherData WeatherAPIResponse
-	if err := json.NewDecoder(weatherResp.Body).Decode(&weatherData); err != nil {
-		log.Printf("Erro ao decodificar resposta do WeatherAPI: %v", err)
-		http.Error(w, "Failed to parse weather information", http.StatusInternalServerError)
-		return
+// cacheHitRatio returns a gauge function reporting the fraction of
+// lookups against c that were cache hits, for use with
+// Metrics.RegisterGauge.
+func cacheHitRatio(c *cache.Counters) func() float64 {
+	return func() float64 {
+		stats := c.Stats()
+		total := stats.Hits + stats.Misses
+		if total == 0 {
+			return 0
+		}
+		return float64(stats.Hits) / float64(total)
 	}
+}
 
-	tempC := weatherData.Current.TempC
-	log.Printf("Temperatura em %s: %.2f°C", cityName, tempC)
+// statusRecorder captures the status code a handler writes, so
+// withMetrics can record it after ServeHTTP returns.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
 
-	// 3. Converter temperaturas
-	tempF := tempC*1.8 + 32
-	tempK := tempC + 273.15 // Usando 273.15 para maior precisão
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
 
-	response := TemperatureResponse{
-		TempC: tempC,
-		TempF: tempF,
-		TempK: tempK,
+// Flush lets statusRecorder sit in front of a streaming handler (e.g.
+// /weather/stream) without hiding the underlying http.Flusher.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK) // 200
-	json.NewEncoder(w).Encode(response)
+// withMetrics wraps next, recording request count and latency under
+// path for every call.
+func withMetrics(metrics *observability.Metrics, path string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next(rec, r)
+
+		metrics.IncCounter("http_requests_total", "Total HTTP requests.", map[string]string{
+			"path": path, "status": strconv.Itoa(rec.status),
+		})
+		metrics.ObserveHistogram("http_request_duration_seconds", "HTTP request latency in seconds.", map[string]string{"path": path}, time.Since(start).Seconds())
+	}
 }
 
 func main() {
-	http.HandleFunc("/weather", handleGetWeather)
+	logger := observability.NewLogger()
+	metrics := observability.NewMetrics()
+
+	providers.OnProviderError = func(kind, provider string) {
+		metrics.IncCounter("provider_errors_total", "Total upstream provider failures.", map[string]string{"kind": kind, "provider": provider})
+	}
+
+	geoChain, weatherChain, forecastChain := newProviderChains()
+
+	geoCache := newCache()
+	weatherCache := newCache()
+	metrics.RegisterGauge("cep_cache_hit_ratio", "Fraction of CEP cache lookups that were hits.", cacheHitRatio(geoCache))
+	metrics.RegisterGauge("weather_cache_hit_ratio", "Fraction of weather cache lookups that were hits.", cacheHitRatio(weatherCache))
+
+	geo := &providers.CachedGeocoder{Next: geoChain, Cache: geoCache, TTL: cepCacheTTL}
+	weather := &providers.CachedWeather{Next: weatherChain, Cache: weatherCache, TTL: weatherCacheTTL}
+
+	http.HandleFunc("/weather", withMetrics(metrics, "/weather", newWeatherHandler(geo, weather, logger)))
+	http.HandleFunc("/weather/batch", withMetrics(metrics, "/weather/batch", newBatchHandler(geo, weather, logger)))
+	http.HandleFunc("/weather/stream", withMetrics(metrics, "/weather/stream", newStreamHandler(geo, weather, logger)))
+	http.HandleFunc("/forecast", withMetrics(metrics, "/forecast", newForecastHandler(geo, forecastChain, logger)))
+	http.HandleFunc("/cache/stats", newCacheStatsHandler(geoCache, weatherCache))
+	http.HandleFunc("/metrics", metrics.Handler())
+	http.HandleFunc("/healthz", observability.HealthzHandler())
+	http.HandleFunc("/readyz", observability.ReadyzHandler())
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080" // Default port for local development
 	}
 
-	log.Printf("Servidor iniciado na porta :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	logger.Info("server starting", slog.String("port", port))
+	if err := http.ListenAndServe(":"+port, nil); err != nil {
+		logger.Error("server stopped", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 }
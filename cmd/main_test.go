@@ -2,27 +2,32 @@ package main
 
 import (
 	"encoding/json"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"strings"
 	"testing"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/providers"
 )
 
-// TestCEPValidation testa a validação do formato do CEP
+// testLogger discards log output so test runs stay quiet.
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// TestCEPValidation testa a validação do formato do CEP, que acontece
+// antes de qualquer chamada a um provider.
 func TestCEPValidation(t *testing.T) {
+	handler := newWeatherHandler(providers.GeocodingChain{}, providers.WeatherChain{}, testLogger())
+
 	tests := []struct {
 		name         string
 		cep          string
 		expectedCode int
 		expectedBody string
 	}{
-		{
-			name:         "Valid CEP format",
-			cep:          "01001000",                     // Will fail later if API key is missing or CEP not found, but format is valid
-			expectedCode: http.StatusInternalServerError, // Expecting 500 because WEATHER_API_KEY is not set in tests
-			expectedBody: "Weather API key not configured",
-		},
 		{
 			name:         "Invalid CEP format - less than 8 digits",
 			cep:          "1234567",
@@ -57,76 +62,45 @@ func TestCEPValidation(t *testing.T) {
 			}
 
 			rr := httptest.NewRecorder()
-			handler := http.HandlerFunc(handleGetWeather)
 			handler.ServeHTTP(rr, req)
 
 			if status := rr.Code; status != tt.expectedCode {
 				t.Errorf("handler returned wrong status code: got %v want %v",
 					status, tt.expectedCode)
 			}
-
-			// For 422 and 404, the body is JSON, for 400 and 500 it's plain text.
-			if strings.Contains(rr.Header().Get("Content-Type"), "application/json") {
-				if strings.TrimSpace(rr.Body.String()) != tt.expectedBody {
-					t.Errorf("handler returned unexpected body: got %v want %v",
-						strings.TrimSpace(rr.Body.String()), tt.expectedBody)
-				}
-			} else {
-				if strings.TrimSpace(rr.Body.String()) != tt.expectedBody {
-					t.Errorf("handler returned unexpected body: got %v want %v",
-						strings.TrimSpace(rr.Body.String()), tt.expectedBody)
-				}
+			if strings.TrimSpace(rr.Body.String()) != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got %v want %v",
+					strings.TrimSpace(rr.Body.String()), tt.expectedBody)
 			}
 		})
 	}
 }
 
-// TestWeatherAPIKeyMissing testa o cenário onde a chave da WeatherAPI está faltando
-func TestWeatherAPIKeyMissing(t *testing.T) {
-	// Garante que a variável de ambiente não está definida para este teste
-	os.Unsetenv("WEATHER_API_KEY")
+// TestUnitsValidation garante que apenas metric, imperial e standard
+// (ou vazio) são aceitos em `units`.
+func TestUnitsValidation(t *testing.T) {
+	handler := newWeatherHandler(providers.GeocodingChain{}, providers.WeatherChain{}, testLogger())
 
-	req, err := http.NewRequest("GET", "/weather?cep=01001000", nil)
+	req, err := http.NewRequest("GET", "/weather?cep=01001000&units=bogus", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(handleGetWeather)
 	handler.ServeHTTP(rr, req)
 
-	if status := rr.Code; status != http.StatusInternalServerError { // 500
+	if status := rr.Code; status != http.StatusBadRequest {
 		t.Errorf("handler returned wrong status code: got %v want %v",
-			status, http.StatusInternalServerError)
-	}
-
-	expected := "Weather API key not configured"
-	if strings.TrimSpace(rr.Body.String()) != expected {
-		t.Errorf("handler returned unexpected body: got %v want %v",
-			strings.TrimSpace(rr.Body.String()), expected)
+			status, http.StatusBadRequest)
 	}
 }
 
-// TestCEPNotFound simula um CEP não encontrado pelo ViaCEP
-// Nota: Este teste não faz uma chamada real ao ViaCEP para evitar dependência externa.
-// Ele assume que a lógica de "erro: true" ou "localidade vazia" do ViaCEP é tratada corretamente.
+// TestCEPNotFound simula um CEP que nenhum GeocodingProvider consegue
+// resolver, usando um provider mockado em vez de depender da rede.
 func TestCEPNotFound(t *testing.T) {
-	// Para este teste, vamos mockar as chamadas HTTP ou usar um CEP que sabemos que o ViaCEP não encontrará.
-	// Para simplicidade, e para não depender de mocks complexos ou de uma API externa,
-	// vamos simular o comportamento esperado para um CEP não encontrado.
-	// Em um cenário real, você poderia usar um mock HTTP client.
-
-	// Para o propósito deste teste, vamos focar na resposta do handler.
-	// Um CEP que o ViaCEP provavelmente não encontrará é 99999999.
-	// Para que este teste funcione, você precisaria de uma chave WEATHER_API_KEY válida,
-	// mas como o ViaCEP falharia primeiro, o erro de API Key não seria relevante aqui.
-
-	// Nota: Este teste pode falhar se o ViaCEP mudar seu comportamento para 99999999.
-	// Uma abordagem mais robusta seria usar um servidor HTTP de mock para o ViaCEP.
-	// No entanto, para a simplicidade solicitada, vamos usar um CEP improvável.
-
-	os.Setenv("WEATHER_API_KEY", "dummy_key") // Precisa de uma chave para passar da validação inicial
-	defer os.Unsetenv("WEATHER_API_KEY")
+	geo := providers.GeocodingChain{&providers.Mock{Cities: map[string]providers.Location{}}}
+	weather := providers.WeatherChain{&providers.Mock{}}
+	handler := newWeatherHandler(geo, weather, testLogger())
 
 	req, err := http.NewRequest("GET", "/weather?cep=99999999", nil)
 	if err != nil {
@@ -134,7 +108,6 @@ func TestCEPNotFound(t *testing.T) {
 	}
 
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(handleGetWeather)
 	handler.ServeHTTP(rr, req)
 
 	if status := rr.Code; status != http.StatusNotFound { // 404
@@ -149,15 +122,43 @@ func TestCEPNotFound(t *testing.T) {
 	}
 }
 
-// TestSuccessScenario testa um cenário de sucesso (requer APIs reais)
-func TestSuccessScenario(t *testing.T) {
-	// Para este teste, você precisará de uma chave WEATHER_API_KEY válida
-	// e acesso à internet para as APIs ViaCEP e WeatherAPI.
-	// Este é mais um teste de integração do que um unitário puro.
-	weatherAPIKey := os.Getenv("WEATHER_API_KEY")
-	if weatherAPIKey == "" {
-		t.Skip("WEATHER_API_KEY not set, skipping success scenario integration test.")
+// TestWeatherProvidersUnavailable testa o failover: se nenhum provider de
+// clima tem uma API key configurada, o serviço responde com erro em vez
+// de travar.
+func TestWeatherProvidersUnavailable(t *testing.T) {
+	geo := providers.GeocodingChain{&providers.Mock{Cities: map[string]providers.Location{
+		"01001000": {City: "São Paulo"},
+	}}}
+	weather := providers.WeatherChain{
+		&providers.WeatherAPI{Client: http.DefaultClient, APIKey: ""},
+		&providers.OpenWeatherMap{Client: http.DefaultClient, APIKey: ""},
 	}
+	handler := newWeatherHandler(geo, weather, testLogger())
+
+	req, err := http.NewRequest("GET", "/weather?cep=01001000", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusInternalServerError {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusInternalServerError)
+	}
+}
+
+// TestSuccessScenario testa o caminho feliz completo com providers
+// mockados, sem nenhuma dependência de rede.
+func TestSuccessScenario(t *testing.T) {
+	geo := providers.GeocodingChain{&providers.Mock{Cities: map[string]providers.Location{
+		"01001000": {City: "São Paulo"},
+	}}}
+	weather := providers.WeatherChain{&providers.Mock{Temps: map[string]float64{
+		"São Paulo": 25.0,
+	}}}
+	handler := newWeatherHandler(geo, weather, testLogger())
 
 	req, err := http.NewRequest("GET", "/weather?cep=01001000", nil) // CEP de São Paulo
 	if err != nil {
@@ -165,33 +166,104 @@ func TestSuccessScenario(t *testing.T) {
 	}
 
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(handleGetWeather)
 	handler.ServeHTTP(rr, req)
 
 	if status := rr.Code; status != http.StatusOK { // 200
-		t.Errorf("handler returned wrong status code: got %v want %v",
-			status, http.StatusOK)
-		t.Errorf("Response body: %s", rr.Body.String())
+		t.Fatalf("handler returned wrong status code: got %v want %v. Body: %s",
+			status, http.StatusOK, rr.Body.String())
 	}
 
 	var resp TemperatureResponse
-	err = json.Unmarshal(rr.Body.Bytes(), &resp)
-	if err != nil {
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
 		t.Fatalf("Failed to unmarshal response: %v", err)
 	}
 
-	// Apenas verifica se os valores não são zero, pois a temperatura real varia
-	if resp.TempC == 0 && resp.TempF == 0 && resp.TempK == 0 {
-		t.Errorf("Expected non-zero temperatures, got %v", resp)
+	if resp.Temp != 25.0 {
+		t.Errorf("got Temp %f, want %f for default (metric) units", resp.Temp, 25.0)
 	}
-	// Poderíamos adicionar mais validações, como verificar se as conversões estão corretas
-	// dado um valor de TempC.
-	expectedF := resp.TempC*1.8 + 32
-	expectedK := resp.TempC + 273.15
-	if resp.TempF != expectedF {
-		t.Errorf("TempF conversion incorrect: got %f, expected %f", resp.TempF, expectedF)
+}
+
+// TestUnitsConversion confirma que `units` muda de fato o valor de Temp
+// retornado, não apenas o rótulo ecoado em Units.
+func TestUnitsConversion(t *testing.T) {
+	geo := providers.GeocodingChain{&providers.Mock{Cities: map[string]providers.Location{
+		"01001000": {City: "São Paulo"},
+	}}}
+	weather := providers.WeatherChain{&providers.Mock{Temps: map[string]float64{
+		"São Paulo": 25.0,
+	}}}
+	handler := newWeatherHandler(geo, weather, testLogger())
+
+	tests := []struct {
+		units string
+		want  float64
+	}{
+		{units: "metric", want: 25.0},
+		{units: "imperial", want: 25.0*1.8 + 32},
+		{units: "standard", want: 25.0 + 273.15},
 	}
-	if resp.TempK != expectedK {
-		t.Errorf("TempK conversion incorrect: got %f, expected %f", resp.TempK, expectedK)
+
+	for _, tt := range tests {
+		t.Run(tt.units, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/weather?cep=01001000&units="+tt.units, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var resp TemperatureResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Temp != tt.want {
+				t.Errorf("units=%s: got Temp %f, want %f", tt.units, resp.Temp, tt.want)
+			}
+			if resp.Units != tt.units {
+				t.Errorf("got Units %q, want %q", resp.Units, tt.units)
+			}
+		})
+	}
+}
+
+// TestCoordinateLookup testa o caminho alternativo via lat/lon, que
+// dispensa geocoding e consulta o clima diretamente.
+func TestCoordinateLookup(t *testing.T) {
+	weather := providers.WeatherChain{&providers.Mock{Temps: map[string]float64{
+		"-23.5505,-46.6333": 25.0,
+	}}}
+	handler := newWeatherHandler(providers.GeocodingChain{}, weather, testLogger())
+
+	req, err := http.NewRequest("GET", "/weather?lat=-23.5505&lon=-46.6333", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v. Body: %s",
+			status, http.StatusOK, rr.Body.String())
+	}
+}
+
+// TestGeocodeZeroCoordinatesUsesCoordinatePath confirms a geocoder that
+// legitimately resolves to (0, 0) still takes the coordinate-based
+// weather lookup, rather than falling back to a by-city lookup because
+// Lat/Lon happen to equal their zero value.
+func TestGeocodeZeroCoordinatesUsesCoordinatePath(t *testing.T) {
+	geo := providers.GeocodingChain{&providers.Mock{Cities: map[string]providers.Location{
+		"01001000": {City: "Null Island", Lat: 0, Lon: 0, HasCoordinates: true},
+	}}}
+	weather := providers.WeatherChain{&providers.Mock{Temps: map[string]float64{
+		"0.0000,0.0000": 25.0,
+	}}}
+	handler := newWeatherHandler(geo, weather, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/weather?cep=01001000", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v. Body: %s",
+			status, http.StatusOK, rr.Body.String())
 	}
 }
@@ -0,0 +1,57 @@
+// Package httpclient provides a shared *http.Client with a bounded
+// timeout, exponential-backoff retries on transient failures, and a
+// per-host circuit breaker. Providers depend on it instead of
+// http.DefaultClient / http.Get so a stalled upstream fails fast rather
+// than letting Cloud Run instances pile up in-flight requests.
+package httpclient
+
+import (
+	"net/http"
+	"time"
+)
+
+// Config controls the behavior of a Client built with New.
+type Config struct {
+	// Timeout bounds a single logical request, retries included.
+	Timeout time.Duration
+	// MaxRetries is how many additional attempts are made after the
+	// first failed one, for 5xx responses and network errors.
+	MaxRetries int
+	// RetryBaseDelay is the base of the exponential backoff between
+	// retries: attempt N waits RetryBaseDelay * 2^(N-1).
+	RetryBaseDelay time.Duration
+	// BreakerThreshold is how many consecutive failures for a given
+	// host trip its circuit breaker open.
+	BreakerThreshold int
+	// BreakerCooldown is how long a tripped breaker stays open before
+	// a request to that host is attempted again.
+	BreakerCooldown time.Duration
+}
+
+// DefaultConfig returns sane defaults for calling third-party HTTP APIs
+// from Cloud Run: a 5s overall timeout, up to 2 retries, and a breaker
+// that trips after 5 consecutive failures and cools down for 30s.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          5 * time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   100 * time.Millisecond,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// New builds an *http.Client whose Transport retries transient failures
+// with exponential backoff and trips a per-host circuit breaker after
+// repeated failures, returning ErrCircuitOpen immediately while a
+// breaker is open.
+func New(cfg Config) *http.Client {
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &roundTripper{
+			next:     http.DefaultTransport,
+			cfg:      cfg,
+			breakers: make(map[string]*breaker),
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a host's circuit breaker is open,
+// signalling callers to fail fast (e.g. map it to a 503) instead of
+// waiting on a request that is very likely to fail anyway.
+var ErrCircuitOpen = errors.New("httpclient: circuit open for host")
+
+// roundTripper wraps another http.RoundTripper with retries and a
+// per-host circuit breaker.
+type roundTripper struct {
+	next http.RoundTripper
+	cfg  Config
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func (rt *roundTripper) breakerFor(host string) *breaker {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	b, ok := rt.breakers[host]
+	if !ok {
+		b = &breaker{}
+		rt.breakers[host] = b
+	}
+	return b
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	b := rt.breakerFor(host)
+
+	if !b.allow(time.Now()) {
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+	}
+
+	// Buffer the body (if any) up front so it can be replayed across
+	// retries; requests to these providers are small GETs/POSTs, never
+	// large uploads.
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt <= rt.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(rt.cfg.RetryBaseDelay * time.Duration(1<<(attempt-1)))
+		}
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, err = rt.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			b.recordSuccess()
+			return resp, nil
+		}
+		// Only close the body when we're about to retry; the final
+		// attempt's response (success or not) is returned to the caller,
+		// who needs its body intact to read it.
+		if err == nil && attempt < rt.cfg.MaxRetries {
+			resp.Body.Close()
+		}
+	}
+
+	b.recordFailure(rt.cfg.BreakerThreshold, rt.cfg.BreakerCooldown, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
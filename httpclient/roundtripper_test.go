@@ -0,0 +1,125 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRetriesOn5xx confirms a transient 5xx response is retried and a
+// later success is returned to the caller.
+func TestRetriesOn5xx(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := New(Config{
+		Timeout:          time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   time.Millisecond,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Second,
+	})
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("got %d calls, want 3 (1 initial + 2 retries)", got)
+	}
+}
+
+// TestRetriesExhaustedBodyReadable confirms that once retries are
+// exhausted against a persistently-5xx upstream, the response handed
+// back to the caller still has a readable body, so callers that
+// inspect it to classify the error (e.g. map to ErrUnavailable) don't
+// get a spurious "read on closed response body" instead.
+func TestRetriesExhaustedBodyReadable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("upstream down"))
+	}))
+	defer server.Close()
+
+	client := New(Config{
+		Timeout:          time.Second,
+		MaxRetries:       1,
+		RetryBaseDelay:   time.Millisecond,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Second,
+	})
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusBadGateway)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expected a readable body, got error: %v", err)
+	}
+	if string(body) != "upstream down" {
+		t.Errorf("got body %q, want %q", body, "upstream down")
+	}
+}
+
+// TestCircuitBreakerTripsAfterThreshold confirms that once a host fails
+// enough consecutive times, further requests fail fast with
+// ErrCircuitOpen instead of hitting the network again.
+func TestCircuitBreakerTripsAfterThreshold(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := New(Config{
+		Timeout:          time.Second,
+		MaxRetries:       0,
+		RetryBaseDelay:   time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+	})
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error on attempt %d: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+
+	callsBeforeTrip := atomic.LoadInt32(&calls)
+
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected circuit breaker error %v, got %v", ErrCircuitOpen, err)
+	}
+
+	if atomic.LoadInt32(&calls) != callsBeforeTrip {
+		t.Errorf("expected no additional network call once the breaker is open, got %d new calls",
+			atomic.LoadInt32(&calls)-callsBeforeTrip)
+	}
+}
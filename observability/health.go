@@ -0,0 +1,22 @@
+package observability
+
+import "net/http"
+
+// HealthzHandler reports liveness for Cloud Run: if the process can
+// handle this request at all, it's healthy.
+func HealthzHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}
+}
+
+// ReadyzHandler reports readiness for Cloud Run. This service has no
+// external dependencies to warm up, so it's ready as soon as it's
+// live.
+func ReadyzHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}
+}
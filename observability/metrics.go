@@ -0,0 +1,195 @@
+package observability
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultLatencyBuckets are the histogram bucket boundaries (in
+// seconds) used for every histogram registered with Metrics.
+var defaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics is a minimal Prometheus-compatible registry: counters,
+// histograms, and gauges that can be scraped in the text exposition
+// format at /metrics.
+type Metrics struct {
+	mu         sync.Mutex
+	counters   map[string]*counterFamily
+	histograms map[string]*histogramFamily
+	gauges     map[string]*gaugeFamily
+}
+
+// NewMetrics creates an empty metrics registry.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		counters:   make(map[string]*counterFamily),
+		histograms: make(map[string]*histogramFamily),
+		gauges:     make(map[string]*gaugeFamily),
+	}
+}
+
+type counterFamily struct {
+	help   string
+	values map[string]float64 // serialized labels -> value
+}
+
+type histogramFamily struct {
+	help    string
+	buckets []float64
+	data    map[string]*histogramData // serialized labels -> data
+}
+
+type histogramData struct {
+	bucketCounts []int64
+	sum          float64
+	count        int64
+}
+
+// gaugeFamily reports a value computed at scrape time, e.g. a cache
+// hit ratio, rather than one accumulated in the registry.
+type gaugeFamily struct {
+	help string
+	fn   func() float64
+}
+
+// labelKey serializes a label set into Prometheus's "{k="v",...}"
+// format, with keys sorted for deterministic output.
+func labelKey(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", k, labels[k])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// IncCounter increments the named counter for the given label set by
+// one, registering it with help text on first use.
+func (m *Metrics) IncCounter(name, help string, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	family, ok := m.counters[name]
+	if !ok {
+		family = &counterFamily{help: help, values: make(map[string]float64)}
+		m.counters[name] = family
+	}
+	family.values[labelKey(labels)]++
+}
+
+// ObserveHistogram records value for the named histogram and label
+// set, registering it with the default latency buckets on first use.
+func (m *Metrics) ObserveHistogram(name, help string, labels map[string]string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	family, ok := m.histograms[name]
+	if !ok {
+		family = &histogramFamily{help: help, buckets: defaultLatencyBuckets, data: make(map[string]*histogramData)}
+		m.histograms[name] = family
+	}
+
+	key := labelKey(labels)
+	data, ok := family.data[key]
+	if !ok {
+		data = &histogramData{bucketCounts: make([]int64, len(family.buckets))}
+		family.data[key] = data
+	}
+
+	data.sum += value
+	data.count++
+	for i, boundary := range family.buckets {
+		if value <= boundary {
+			data.bucketCounts[i]++
+		}
+	}
+}
+
+// RegisterGauge registers a gauge whose value is computed by calling
+// fn at scrape time, used for point-in-time readings like cache hit
+// ratios that aren't naturally accumulated.
+func (m *Metrics) RegisterGauge(name, help string, fn func() float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gauges[name] = &gaugeFamily{help: help, fn: fn}
+}
+
+// Handler renders the registry in the Prometheus text exposition
+// format.
+func (m *Metrics) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		for _, name := range sortedKeys(m.counters) {
+			family := m.counters[name]
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, family.help, name)
+			for _, lk := range sortedKeys(family.values) {
+				fmt.Fprintf(w, "%s%s %g\n", name, lk, family.values[lk])
+			}
+		}
+
+		for _, name := range sortedKeys(m.histograms) {
+			family := m.histograms[name]
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, family.help, name)
+			for _, lk := range sortedKeys(family.data) {
+				writeHistogramData(w, name, lk, family.buckets, family.data[lk])
+			}
+		}
+
+		for _, name := range sortedKeys(m.gauges) {
+			family := m.gauges[name]
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", name, family.help, name, name, family.fn())
+		}
+	}
+}
+
+func writeHistogramData(w http.ResponseWriter, name, labels string, buckets []float64, data *histogramData) {
+	prefix := bucketLabelPrefix(labels)
+	for i, boundary := range buckets {
+		fmt.Fprintf(w, "%s_bucket{%sle=\"%g\"} %d\n", name, prefix, boundary, data.bucketCounts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, prefix, data.count)
+	fmt.Fprintf(w, "%s_sum%s %g\n", name, labels, data.sum)
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, data.count)
+}
+
+// bucketLabelPrefix turns a serialized "{k="v"}" label set into the
+// prefix needed before appending "le=...", e.g. `k="v",` or "" when
+// there are no other labels.
+func bucketLabelPrefix(labels string) string {
+	trimmed := strings.TrimPrefix(strings.TrimSuffix(labels, "}"), "{")
+	if trimmed == "" {
+		return ""
+	}
+	return trimmed + ","
+}
+
+// sortedKeys returns the keys of a string-keyed map in sorted order,
+// so /metrics output is deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
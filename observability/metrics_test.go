@@ -0,0 +1,50 @@
+package observability
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandlerRendersCounter(t *testing.T) {
+	m := NewMetrics()
+	m.IncCounter("http_requests_total", "Total HTTP requests.", map[string]string{"path": "/weather"})
+	m.IncCounter("http_requests_total", "Total HTTP requests.", map[string]string{"path": "/weather"})
+
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rr.Body.String()
+	if !strings.Contains(body, `http_requests_total{path="/weather"} 2`) {
+		t.Errorf("expected counter value 2 in output, got:\n%s", body)
+	}
+}
+
+func TestMetricsHandlerRendersHistogram(t *testing.T) {
+	m := NewMetrics()
+	m.ObserveHistogram("http_request_duration_seconds", "Latency.", map[string]string{"path": "/weather"}, 0.2)
+
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rr.Body.String()
+	if !strings.Contains(body, `http_request_duration_seconds_count{path="/weather"} 1`) {
+		t.Errorf("expected histogram count in output, got:\n%s", body)
+	}
+	if !strings.Contains(body, `http_request_duration_seconds_bucket{path="/weather",le="+Inf"} 1`) {
+		t.Errorf("expected +Inf bucket in output, got:\n%s", body)
+	}
+}
+
+func TestMetricsHandlerRendersGauge(t *testing.T) {
+	m := NewMetrics()
+	m.RegisterGauge("cep_cache_hit_ratio", "Fraction of CEP cache lookups that were hits.", func() float64 { return 0.75 })
+
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rr.Body.String()
+	if !strings.Contains(body, "cep_cache_hit_ratio 0.75") {
+		t.Errorf("expected gauge value in output, got:\n%s", body)
+	}
+}
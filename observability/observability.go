@@ -0,0 +1,21 @@
+// Package observability provides the cross-cutting logging, tracing,
+// and metrics primitives used by the weather service: a structured
+// slog logger, a minimal W3C Trace Context-compatible tracer, and a
+// Prometheus-compatible metrics registry exposed at /metrics. The
+// tracer and registry are hand-rolled rather than built on the
+// OpenTelemetry/Prometheus client libraries, since this project has no
+// external dependencies; they follow the same wire formats so a real
+// collector can still scrape/ingest them.
+package observability
+
+import (
+	"log/slog"
+	"os"
+)
+
+// NewLogger returns a JSON slog.Logger writing to stdout, matching
+// Cloud Run's expectation of structured logs on stdout/stderr.
+func NewLogger() *slog.Logger {
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})
+	return slog.New(handler)
+}
@@ -0,0 +1,108 @@
+package observability
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TraceContext carries the W3C Trace Context identifiers for a
+// request, propagated to outbound calls via the traceparent header.
+type TraceContext struct {
+	TraceID string
+	SpanID  string
+}
+
+type traceContextKey struct{}
+
+// NewTraceContext creates a fresh trace id and root span id, used when
+// an inbound request doesn't already carry a traceparent header.
+func NewTraceContext() TraceContext {
+	return TraceContext{TraceID: randomHex(16), SpanID: randomHex(8)}
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// ParseTraceParent extracts the trace id and parent span id from a W3C
+// `traceparent` header value ("00-<trace-id>-<parent-id>-<flags>"). It
+// returns a fresh TraceContext if the header is missing or malformed.
+func ParseTraceParent(header string) TraceContext {
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return NewTraceContext()
+	}
+	return TraceContext{TraceID: parts[1], SpanID: parts[2]}
+}
+
+// Header formats a traceparent header value for an outbound call that
+// continues this trace, minting a new span id as the outbound parent.
+func (tc TraceContext) Header() string {
+	return fmt.Sprintf("00-%s-%s-01", tc.TraceID, randomHex(8))
+}
+
+// WithTraceContext attaches tc to ctx so it can be picked back up by
+// StartSpan or Inject further down the call stack.
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceContextFromContext returns the TraceContext attached to ctx, or
+// a fresh one if none was attached.
+func TraceContextFromContext(ctx context.Context) TraceContext {
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	if !ok {
+		return NewTraceContext()
+	}
+	return tc
+}
+
+// Inject sets the traceparent header on an outbound request from the
+// TraceContext carried by ctx, so the provider being called can be
+// correlated back to the request that triggered it.
+func Inject(ctx context.Context, req *http.Request) {
+	tc := TraceContextFromContext(ctx)
+	req.Header.Set("traceparent", tc.Header())
+}
+
+// StartSpan begins a span named name under the trace carried by ctx,
+// minting its own span id as a child of whatever span (if any) ctx
+// already carries. It returns a context carrying the new span as the
+// current one, so nested StartSpan calls record it as their parent
+// (Inject carries its trace id but sends a freshly minted span id as
+// the outbound parent), and a function that ends the span, logging its outcome and
+// duration. Call the returned function with the error the traced work
+// produced, or nil on success.
+func StartSpan(ctx context.Context, logger *slog.Logger, name string) (context.Context, func(err error)) {
+	parent := TraceContextFromContext(ctx)
+	tc := TraceContext{TraceID: parent.TraceID, SpanID: randomHex(8)}
+	ctx = WithTraceContext(ctx, tc)
+	startedAt := time.Now()
+
+	end := func(err error) {
+		attrs := []any{
+			slog.String("span", name),
+			slog.String("trace_id", tc.TraceID),
+			slog.String("span_id", tc.SpanID),
+		}
+		if parent.SpanID != "" {
+			attrs = append(attrs, slog.String("parent_span_id", parent.SpanID))
+		}
+		attrs = append(attrs, slog.Duration("duration", time.Since(startedAt)))
+		if err != nil {
+			logger.Error("span failed", append(attrs, slog.String("error", err.Error()))...)
+			return
+		}
+		logger.Info("span completed", attrs...)
+	}
+
+	return ctx, end
+}
@@ -0,0 +1,56 @@
+package observability
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestParseTraceParentValid(t *testing.T) {
+	header := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	tc := ParseTraceParent(header)
+
+	if tc.TraceID != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("got trace id %q", tc.TraceID)
+	}
+	if tc.SpanID != "b7ad6b7169203331" {
+		t.Errorf("got span id %q", tc.SpanID)
+	}
+}
+
+func TestParseTraceParentMalformedGeneratesFresh(t *testing.T) {
+	tc := ParseTraceParent("not-a-traceparent")
+	if tc.TraceID == "" || tc.SpanID == "" {
+		t.Errorf("expected a fresh trace context, got %+v", tc)
+	}
+}
+
+// TestStartSpanMintsChildSpanID confirms a nested span gets its own
+// span id, distinct from its parent, so consumers can reconstruct
+// parent/child structure instead of seeing a flat list of same-span
+// log lines.
+func TestStartSpanMintsChildSpanID(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	root := NewTraceContext()
+	ctx := WithTraceContext(context.Background(), root)
+
+	parentCtx, endParent := StartSpan(ctx, logger, "parent")
+	parentTC := TraceContextFromContext(parentCtx)
+	if parentTC.SpanID == "" || parentTC.SpanID == root.SpanID {
+		t.Errorf("expected parent span to mint a fresh span id, got %q (root was %q)", parentTC.SpanID, root.SpanID)
+	}
+	if parentTC.TraceID != root.TraceID {
+		t.Errorf("expected trace id to stay %q, got %q", root.TraceID, parentTC.TraceID)
+	}
+
+	childCtx, endChild := StartSpan(parentCtx, logger, "child")
+	childTC := TraceContextFromContext(childCtx)
+	if childTC.SpanID == "" || childTC.SpanID == parentTC.SpanID {
+		t.Errorf("expected child span to mint its own span id, got %q (parent was %q)", childTC.SpanID, parentTC.SpanID)
+	}
+
+	endChild(nil)
+	endParent(nil)
+}
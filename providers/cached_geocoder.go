@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/cache"
+)
+
+// cepCacheKeyPrefix namespaces CEP cache entries so they can safely
+// share a Redis instance with weather cache entries.
+const cepCacheKeyPrefix = "cep:"
+
+// CachedGeocoder wraps a GeocodingProvider with a TTL cache keyed by
+// CEP. Postal codes rarely change, so TTL is expected to be long
+// (days).
+type CachedGeocoder struct {
+	Next  GeocodingProvider
+	Cache cache.Cache
+	TTL   time.Duration
+}
+
+func (c *CachedGeocoder) Name() string { return c.Next.Name() }
+
+func (c *CachedGeocoder) Geocode(ctx context.Context, cep string) (Location, error) {
+	key := cepCacheKeyPrefix + cep
+
+	if cached, ok := c.Cache.Get(key); ok {
+		return decodeLocation(cached), nil
+	}
+
+	loc, err := c.Next.Geocode(ctx, cep)
+	if err != nil {
+		return Location{}, err
+	}
+
+	c.Cache.Set(key, encodeLocation(loc), c.TTL)
+	return loc, nil
+}
+
+// encodeLocation/decodeLocation serialize a Location to a flat string
+// so it can be stored by Cache implementations, which only deal in
+// strings.
+func encodeLocation(loc Location) string {
+	hasCoords := "0"
+	if loc.HasCoordinates {
+		hasCoords = "1"
+	}
+	return fmt.Sprintf("%s\x1f%f\x1f%f\x1f%s", loc.City, loc.Lat, loc.Lon, hasCoords)
+}
+
+func decodeLocation(s string) Location {
+	parts := strings.SplitN(s, "\x1f", 4)
+	if len(parts) != 4 {
+		return Location{City: s}
+	}
+	lat, _ := strconv.ParseFloat(parts[1], 64)
+	lon, _ := strconv.ParseFloat(parts[2], 64)
+	return Location{City: parts[0], Lat: lat, Lon: lon, HasCoordinates: parts[3] == "1"}
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/cache"
+)
+
+// countingGeocoder counts how many times Geocode is actually invoked,
+// so tests can confirm the cache is preventing repeat calls.
+type countingGeocoder struct {
+	Mock
+	calls int
+}
+
+func (c *countingGeocoder) Geocode(ctx context.Context, cep string) (Location, error) {
+	c.calls++
+	return c.Mock.Geocode(ctx, cep)
+}
+
+func TestCachedGeocoderAvoidsRepeatCalls(t *testing.T) {
+	next := &countingGeocoder{Mock: Mock{Cities: map[string]Location{
+		"01001000": {City: "São Paulo"},
+	}}}
+	cached := &CachedGeocoder{Next: next, Cache: cache.NewLRU(10), TTL: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		loc, err := cached.Geocode(context.Background(), "01001000")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if loc.City != "São Paulo" {
+			t.Fatalf("got city %q, want São Paulo", loc.City)
+		}
+	}
+
+	if next.calls != 1 {
+		t.Errorf("got %d calls to the underlying provider, want 1", next.calls)
+	}
+}
+
+type countingWeather struct {
+	Mock
+	calls int
+}
+
+func (c *countingWeather) CurrentByCity(ctx context.Context, city string, units Units) (Conditions, error) {
+	c.calls++
+	return c.Mock.CurrentByCity(ctx, city, units)
+}
+
+func TestCachedWeatherAvoidsRepeatCalls(t *testing.T) {
+	next := &countingWeather{Mock: Mock{Temps: map[string]float64{
+		"São Paulo": 25.0,
+	}}}
+	cached := &CachedWeather{Next: next, Cache: cache.NewLRU(10), TTL: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		cond, err := cached.CurrentByCity(context.Background(), "São Paulo", UnitsMetric)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cond.TempC != 25.0 {
+			t.Fatalf("got temp %f, want 25.0", cond.TempC)
+		}
+	}
+
+	if next.calls != 1 {
+		t.Errorf("got %d calls to the underlying provider, want 1", next.calls)
+	}
+}
@@ -0,0 +1,71 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/cache"
+)
+
+// weatherCacheKeyPrefix namespaces weather cache entries so they can
+// safely share a Redis instance with CEP cache entries.
+const weatherCacheKeyPrefix = "weather:"
+
+// CachedWeather wraps a WeatherProvider with a short-TTL cache keyed by
+// normalized city name (or coordinates), since temperatures change
+// minute to minute, unlike the city a CEP resolves to.
+type CachedWeather struct {
+	Next  WeatherProvider
+	Cache cache.Cache
+	TTL   time.Duration
+}
+
+func (c *CachedWeather) Name() string { return c.Next.Name() }
+
+func (c *CachedWeather) CurrentByCity(ctx context.Context, city string, units Units) (Conditions, error) {
+	key := weatherCacheKeyPrefix + normalizeCity(city) + "|" + string(units)
+
+	if cached, ok := c.Cache.Get(key); ok {
+		return decodeConditions(cached, units), nil
+	}
+
+	cond, err := c.Next.CurrentByCity(ctx, city, units)
+	if err != nil {
+		return Conditions{}, err
+	}
+
+	c.Cache.Set(key, encodeConditions(cond), c.TTL)
+	return cond, nil
+}
+
+func (c *CachedWeather) CurrentByCoordinates(ctx context.Context, lat, lon float64, units Units) (Conditions, error) {
+	key := fmt.Sprintf("%s%.4f,%.4f|%s", weatherCacheKeyPrefix, lat, lon, units)
+
+	if cached, ok := c.Cache.Get(key); ok {
+		return decodeConditions(cached, units), nil
+	}
+
+	cond, err := c.Next.CurrentByCoordinates(ctx, lat, lon, units)
+	if err != nil {
+		return Conditions{}, err
+	}
+
+	c.Cache.Set(key, encodeConditions(cond), c.TTL)
+	return cond, nil
+}
+
+func normalizeCity(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+func encodeConditions(cond Conditions) string {
+	return strconv.FormatFloat(cond.TempC, 'f', -1, 64)
+}
+
+func decodeConditions(s string, units Units) Conditions {
+	tempC, _ := strconv.ParseFloat(s, 64)
+	return Conditions{TempC: tempC, Units: units}
+}
@@ -0,0 +1,145 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// OnProviderError, when non-nil, is called whenever a provider within a
+// chain fails and the chain falls over to the next one. kind is
+// "geocoding", "weather", or "forecast". It lets callers (e.g. cmd/main.go) feed a
+// Prometheus counter without this package depending on any particular
+// metrics backend.
+var OnProviderError func(kind, provider string)
+
+func notifyProviderError(kind, provider string) {
+	if OnProviderError != nil {
+		OnProviderError(kind, provider)
+	}
+}
+
+// GeocodingChain tries each provider in order, falling back to the next
+// one when a provider fails with any error other than ErrNotFound (e.g.
+// ErrUnavailable when it is down or rate-limited). A provider reporting
+// ErrNotFound is treated as authoritative and stops the chain
+// immediately.
+type GeocodingChain []GeocodingProvider
+
+// Name identifies the chain as a whole, so it can be used anywhere a
+// single GeocodingProvider is expected (e.g. wrapped by CachedGeocoder).
+func (c GeocodingChain) Name() string { return "geocoding-chain" }
+
+func (c GeocodingChain) Geocode(ctx context.Context, cep string) (Location, error) {
+	var lastErr error
+	for _, p := range c {
+		loc, err := p.Geocode(ctx, cep)
+		if err == nil {
+			return loc, nil
+		}
+		if errors.Is(err, ErrNotFound) {
+			return Location{}, err
+		}
+		log.Printf("providers: %s geocoding failed, trying next provider: %v", p.Name(), err)
+		notifyProviderError("geocoding", p.Name())
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ErrUnavailable
+	}
+	return Location{}, lastErr
+}
+
+// WeatherChain mirrors GeocodingChain for WeatherProvider lookups.
+type WeatherChain []WeatherProvider
+
+// Name identifies the chain as a whole, so it can be used anywhere a
+// single WeatherProvider is expected (e.g. wrapped by CachedWeather).
+func (c WeatherChain) Name() string { return "weather-chain" }
+
+func (c WeatherChain) CurrentByCity(ctx context.Context, city string, units Units) (Conditions, error) {
+	var lastErr error
+	for _, p := range c {
+		cond, err := p.CurrentByCity(ctx, city, units)
+		if err == nil {
+			return cond, nil
+		}
+		if errors.Is(err, ErrNotFound) {
+			return Conditions{}, err
+		}
+		log.Printf("providers: %s weather lookup failed, trying next provider: %v", p.Name(), err)
+		notifyProviderError("weather", p.Name())
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ErrUnavailable
+	}
+	return Conditions{}, lastErr
+}
+
+func (c WeatherChain) CurrentByCoordinates(ctx context.Context, lat, lon float64, units Units) (Conditions, error) {
+	var lastErr error
+	for _, p := range c {
+		cond, err := p.CurrentByCoordinates(ctx, lat, lon, units)
+		if err == nil {
+			return cond, nil
+		}
+		if errors.Is(err, ErrNotFound) {
+			return Conditions{}, err
+		}
+		log.Printf("providers: %s weather lookup failed, trying next provider: %v", p.Name(), err)
+		notifyProviderError("weather", p.Name())
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ErrUnavailable
+	}
+	return Conditions{}, lastErr
+}
+
+// ForecastChain mirrors WeatherChain for ForecastProvider lookups.
+type ForecastChain []ForecastProvider
+
+// Name identifies the chain as a whole, so it can be used anywhere a
+// single ForecastProvider is expected.
+func (c ForecastChain) Name() string { return "forecast-chain" }
+
+func (c ForecastChain) ForecastByCity(ctx context.Context, city string, days int, units Units) (Forecast, error) {
+	var lastErr error
+	for _, p := range c {
+		f, err := p.ForecastByCity(ctx, city, days, units)
+		if err == nil {
+			return f, nil
+		}
+		if errors.Is(err, ErrNotFound) {
+			return Forecast{}, err
+		}
+		log.Printf("providers: %s forecast lookup failed, trying next provider: %v", p.Name(), err)
+		notifyProviderError("forecast", p.Name())
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ErrUnavailable
+	}
+	return Forecast{}, lastErr
+}
+
+func (c ForecastChain) ForecastByCoordinates(ctx context.Context, lat, lon float64, days int, units Units) (Forecast, error) {
+	var lastErr error
+	for _, p := range c {
+		f, err := p.ForecastByCoordinates(ctx, lat, lon, days, units)
+		if err == nil {
+			return f, nil
+		}
+		if errors.Is(err, ErrNotFound) {
+			return Forecast{}, err
+		}
+		log.Printf("providers: %s forecast lookup failed, trying next provider: %v", p.Name(), err)
+		notifyProviderError("forecast", p.Name())
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ErrUnavailable
+	}
+	return Forecast{}, lastErr
+}
@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"context"
+	"time"
+)
+
+// Condition is a stable, provider-independent classification of sky
+// conditions. Providers map their own proprietary condition codes onto
+// this enum, so callers never need to know about provider-specific
+// condition codes.
+type Condition int
+
+const (
+	ConditionUnknown Condition = iota
+	ConditionClear
+	ConditionClouds
+	ConditionRain
+	ConditionSnow
+	ConditionThunderstorm
+	ConditionFog
+)
+
+// Label returns a human-readable label for c.
+func (c Condition) Label() string {
+	switch c {
+	case ConditionClear:
+		return "clear"
+	case ConditionClouds:
+		return "clouds"
+	case ConditionRain:
+		return "rain"
+	case ConditionSnow:
+		return "snow"
+	case ConditionThunderstorm:
+		return "thunderstorm"
+	case ConditionFog:
+		return "fog"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// DateFormat is the forecast-day layout shared by every provider's
+	// forecast payload, used to parse forecast dates and to pair them
+	// with a provider's sunrise/sunset clock string.
+	DateFormat = "2006-01-02"
+	// hourlyTimeFormat is DateFormat plus a 24-hour clock, matching the
+	// hourly forecast timestamps providers report.
+	hourlyTimeFormat = DateFormat + " 15:04"
+	// sunTimeFormat is DateFormat plus a 12-hour clock, matching the
+	// "hh:mm AM/PM" sunrise/sunset strings providers report.
+	sunTimeFormat = DateFormat + " 03:04 PM"
+)
+
+// parseSunTime combines a forecast day with a provider's 12-hour
+// "hh:mm AM/PM" sunrise/sunset string into a single time.Time.
+func parseSunTime(day time.Time, clock string) (time.Time, error) {
+	return time.Parse(sunTimeFormat, day.Format(DateFormat)+" "+clock)
+}
+
+// HourlyForecast is one hour of forecast data for a location. TempC is
+// always Celsius, mirroring Conditions.
+type HourlyForecast struct {
+	Time             time.Time
+	TempC            float64
+	PrecipitationPct float64
+	WindKPH          float64
+	Condition        Condition
+}
+
+// DailyForecast is one day of forecast data for a location. Sunrise
+// and Sunset are the zero time.Time when a provider doesn't report
+// them.
+type DailyForecast struct {
+	Date             time.Time
+	MinTempC         float64
+	MaxTempC         float64
+	PrecipitationPct float64
+	WindKPH          float64
+	Sunrise          time.Time
+	Sunset           time.Time
+	Condition        Condition
+}
+
+// Forecast is the result of a forecast lookup: hourly and daily points
+// for the requested range, plus the Units the caller asked for.
+type Forecast struct {
+	Units  Units
+	Hourly []HourlyForecast
+	Daily  []DailyForecast
+}
+
+// ForecastProvider is implemented by weather providers that can return
+// multi-day forecasts in addition to current conditions.
+type ForecastProvider interface {
+	Name() string
+	ForecastByCity(ctx context.Context, city string, days int, units Units) (Forecast, error)
+	ForecastByCoordinates(ctx context.Context, lat, lon float64, days int, units Units) (Forecast, error)
+}
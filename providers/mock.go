@@ -0,0 +1,52 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+)
+
+// Mock is an in-memory GeocodingProvider, WeatherProvider, and
+// ForecastProvider for tests. It never touches the network, which
+// keeps unit tests fast and deterministic.
+type Mock struct {
+	// Cities maps a CEP to the Location it should resolve to.
+	Cities map[string]Location
+	// Temps maps a city name to a temperature in Celsius.
+	Temps map[string]float64
+	// Forecasts maps a city name to the Forecast it should resolve to.
+	Forecasts map[string]Forecast
+}
+
+func (m *Mock) Name() string { return "mock" }
+
+func (m *Mock) Geocode(ctx context.Context, cep string) (Location, error) {
+	loc, ok := m.Cities[cep]
+	if !ok {
+		return Location{}, ErrNotFound
+	}
+	return loc, nil
+}
+
+func (m *Mock) CurrentByCity(ctx context.Context, city string, units Units) (Conditions, error) {
+	tempC, ok := m.Temps[city]
+	if !ok {
+		return Conditions{}, ErrNotFound
+	}
+	return Conditions{TempC: tempC, Units: units}, nil
+}
+
+func (m *Mock) CurrentByCoordinates(ctx context.Context, lat, lon float64, units Units) (Conditions, error) {
+	return m.CurrentByCity(ctx, fmt.Sprintf("%.4f,%.4f", lat, lon), units)
+}
+
+func (m *Mock) ForecastByCity(ctx context.Context, city string, days int, units Units) (Forecast, error) {
+	f, ok := m.Forecasts[city]
+	if !ok {
+		return Forecast{}, ErrNotFound
+	}
+	return f, nil
+}
+
+func (m *Mock) ForecastByCoordinates(ctx context.Context, lat, lon float64, days int, units Units) (Forecast, error) {
+	return m.ForecastByCity(ctx, fmt.Sprintf("%.4f,%.4f", lat, lon), days, units)
+}
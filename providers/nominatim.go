@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/observability"
+)
+
+// defaultNominatimBaseURL is the production Nominatim endpoint.
+const defaultNominatimBaseURL = "https://nominatim.openstreetmap.org"
+
+// Nominatim is a coordinate-capable fallback geocoder backed by
+// OpenStreetMap's Nominatim search API. Placed after a primary geocoder
+// (e.g. ViaCEP) in a GeocodingChain, it is consulted only when the
+// primary fails with an error other than ErrNotFound; a postal code the
+// primary reports as not found, or a primary result without lat/lon, is
+// returned as is.
+type Nominatim struct {
+	Client *http.Client
+	// BaseURL overrides the Nominatim endpoint, defaulting to
+	// defaultNominatimBaseURL. Tests point it at an httptest.Server so
+	// they run fully offline.
+	BaseURL string
+}
+
+func (n *Nominatim) Name() string { return "nominatim" }
+
+func (n *Nominatim) baseURL() string {
+	if n.BaseURL != "" {
+		return n.BaseURL
+	}
+	return defaultNominatimBaseURL
+}
+
+type nominatimResult struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+}
+
+func (n *Nominatim) Geocode(ctx context.Context, cep string) (Location, error) {
+	reqURL := fmt.Sprintf("%s/search?postalcode=%s&format=json&limit=1", n.baseURL(), cep)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return Location{}, err
+	}
+	// Nominatim's usage policy requires a descriptive User-Agent.
+	req.Header.Set("User-Agent", "go-googlecloudrun-api-demo/1.0")
+	observability.Inject(ctx, req)
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return Location{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return Location{}, fmt.Errorf("%w: nominatim status %d", ErrUnavailable, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Location{}, fmt.Errorf("nominatim status %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return Location{}, fmt.Errorf("nominatim: decode response: %w", err)
+	}
+	if len(results) == 0 {
+		return Location{}, ErrNotFound
+	}
+
+	lat, lon, err := parseLatLon(results[0].Lat, results[0].Lon)
+	if err != nil {
+		return Location{}, fmt.Errorf("nominatim: parse coordinates: %w", err)
+	}
+
+	return Location{City: results[0].DisplayName, Lat: lat, Lon: lon, HasCoordinates: true}, nil
+}
@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestNominatimGeocodeSuccess confirms a successful Nominatim lookup
+// resolves to the first result's display name and coordinates.
+func TestNominatimGeocodeSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"lat":"-23.5505","lon":"-46.6333","display_name":"São Paulo, Brazil"}]`)
+	}))
+	defer server.Close()
+
+	n := &Nominatim{Client: server.Client(), BaseURL: server.URL}
+
+	loc, err := n.Geocode(context.Background(), "01001000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loc.City != "São Paulo, Brazil" {
+		t.Errorf("got city %q, want %q", loc.City, "São Paulo, Brazil")
+	}
+	if loc.Lat != -23.5505 || loc.Lon != -46.6333 {
+		t.Errorf("got coordinates (%f, %f), want (-23.5505, -46.6333)", loc.Lat, loc.Lon)
+	}
+}
+
+// TestNominatimGeocodeNotFound confirms an empty result set is surfaced
+// as ErrNotFound.
+func TestNominatimGeocodeNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	n := &Nominatim{Client: server.Client(), BaseURL: server.URL}
+
+	_, err := n.Geocode(context.Background(), "99999999")
+	if err != ErrNotFound {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
+
+// TestNominatimGeocodeUnavailable confirms a 5xx from Nominatim is
+// surfaced as ErrUnavailable so the GeocodingChain fails over instead of
+// giving up.
+func TestNominatimGeocodeUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	n := &Nominatim{Client: server.Client(), BaseURL: server.URL}
+
+	_, err := n.Geocode(context.Background(), "01001000")
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got error %v, want %v", err, ErrUnavailable)
+	}
+}
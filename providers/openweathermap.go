@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/observability"
+)
+
+// defaultOpenWeatherMapBaseURL is the production OpenWeatherMap endpoint.
+const defaultOpenWeatherMapBaseURL = "https://api.openweathermap.org"
+
+// OpenWeatherMap fetches current conditions from openweathermap.org,
+// used as a failover when WeatherAPI is unavailable or rate-limited.
+type OpenWeatherMap struct {
+	Client *http.Client
+	APIKey string
+	// BaseURL overrides the OpenWeatherMap endpoint, defaulting to
+	// defaultOpenWeatherMapBaseURL. Tests point it at an
+	// httptest.Server so they run fully offline.
+	BaseURL string
+}
+
+func (o *OpenWeatherMap) Name() string { return "openweathermap" }
+
+func (o *OpenWeatherMap) baseURL() string {
+	if o.BaseURL != "" {
+		return o.BaseURL
+	}
+	return defaultOpenWeatherMapBaseURL
+}
+
+type openWeatherMapResponse struct {
+	Main struct {
+		Temp float64 `json:"temp"`
+	} `json:"main"`
+}
+
+func (o *OpenWeatherMap) CurrentByCity(ctx context.Context, city string, units Units) (Conditions, error) {
+	return o.current(ctx, fmt.Sprintf("q=%s", url.QueryEscape(city)), units)
+}
+
+func (o *OpenWeatherMap) CurrentByCoordinates(ctx context.Context, lat, lon float64, units Units) (Conditions, error) {
+	return o.current(ctx, fmt.Sprintf("lat=%f&lon=%f", lat, lon), units)
+}
+
+// current fetches from OWM in metric units regardless of the caller's
+// requested Units, so Conditions.TempC is always Celsius; Units is
+// carried through only as response metadata.
+func (o *OpenWeatherMap) current(ctx context.Context, query string, units Units) (Conditions, error) {
+	if o.APIKey == "" {
+		return Conditions{}, fmt.Errorf("openweathermap: API key not configured")
+	}
+
+	reqURL := fmt.Sprintf("%s/data/2.5/weather?appid=%s&units=metric&%s", o.baseURL(), o.APIKey, query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return Conditions{}, err
+	}
+	observability.Inject(ctx, req)
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return Conditions{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Conditions{}, ErrNotFound
+	}
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return Conditions{}, fmt.Errorf("%w: openweathermap status %d", ErrUnavailable, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Conditions{}, fmt.Errorf("openweathermap status %d", resp.StatusCode)
+	}
+
+	var data openWeatherMapResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return Conditions{}, fmt.Errorf("openweathermap: decode response: %w", err)
+	}
+
+	return Conditions{TempC: data.Main.Temp, Units: units}, nil
+}
@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestOpenWeatherMapCurrentSuccess confirms a successful OpenWeatherMap
+// lookup resolves to the returned Celsius temperature.
+func TestOpenWeatherMapCurrentSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"main":{"temp":21.5}}`)
+	}))
+	defer server.Close()
+
+	o := &OpenWeatherMap{Client: server.Client(), APIKey: "key", BaseURL: server.URL}
+
+	cond, err := o.CurrentByCity(context.Background(), "São Paulo", UnitsMetric)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cond.TempC != 21.5 {
+		t.Errorf("got TempC %f, want %f", cond.TempC, 21.5)
+	}
+}
+
+// TestOpenWeatherMapCurrentNotFound confirms a 404 from OpenWeatherMap
+// is surfaced as ErrNotFound.
+func TestOpenWeatherMapCurrentNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	o := &OpenWeatherMap{Client: server.Client(), APIKey: "key", BaseURL: server.URL}
+
+	_, err := o.CurrentByCity(context.Background(), "Nowhere", UnitsMetric)
+	if err != ErrNotFound {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
+
+// TestOpenWeatherMapCurrentUnavailable confirms a 5xx from OpenWeatherMap
+// is surfaced as ErrUnavailable so the WeatherChain fails over instead
+// of giving up.
+func TestOpenWeatherMapCurrentUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	o := &OpenWeatherMap{Client: server.Client(), APIKey: "key", BaseURL: server.URL}
+
+	_, err := o.CurrentByCity(context.Background(), "São Paulo", UnitsMetric)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got error %v, want %v", err, ErrUnavailable)
+	}
+}
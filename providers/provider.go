@@ -0,0 +1,70 @@
+// Package providers defines the pluggable geocoding and weather data
+// sources used by the weather service. Each capability (resolving a
+// postal code, fetching current conditions, fetching a forecast) is
+// expressed as an interface with several implementations, so the
+// primary provider can fail over to a secondary one instead of the
+// whole request failing.
+package providers
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by a provider when the requested postal code,
+// city, or coordinate pair could not be resolved. Unlike ErrUnavailable,
+// it is authoritative: a chain stops and surfaces it immediately instead
+// of trying the next provider.
+var ErrNotFound = errors.New("providers: location not found")
+
+// ErrUnavailable is returned (or wrapped) by a provider when it is
+// rate-limited, timing out, or otherwise temporarily unable to serve the
+// request. A chain treats it as a signal to fail over to the next
+// provider rather than giving up.
+var ErrUnavailable = errors.New("providers: provider unavailable")
+
+// Location is the result of resolving a postal code to a city and,
+// when the provider supports it, geographic coordinates. HasCoordinates
+// reports whether Lat/Lon were actually resolved, since a provider
+// legitimately sitting on the equator/prime meridian would otherwise be
+// indistinguishable from one that never returned coordinates at all.
+type Location struct {
+	City           string
+	Lat            float64
+	Lon            float64
+	HasCoordinates bool
+}
+
+// GeocodingProvider resolves a postal code into a Location.
+type GeocodingProvider interface {
+	// Name identifies the provider in logs and error messages.
+	Name() string
+	Geocode(ctx context.Context, cep string) (Location, error)
+}
+
+// Units selects the unit system a caller would like a response
+// expressed in, mirroring the values accepted by most public weather
+// APIs.
+type Units string
+
+const (
+	UnitsMetric   Units = "metric"
+	UnitsImperial Units = "imperial"
+	UnitsStandard Units = "standard"
+)
+
+// Conditions is the current weather for a location. TempC is always
+// Celsius regardless of the Units requested; Units records what the
+// caller asked for so handlers can echo it back in the response.
+type Conditions struct {
+	TempC float64
+	Units Units
+}
+
+// WeatherProvider fetches current conditions for a location, either by
+// city name or by coordinates when the provider supports it.
+type WeatherProvider interface {
+	Name() string
+	CurrentByCity(ctx context.Context, city string, units Units) (Conditions, error)
+	CurrentByCoordinates(ctx context.Context, lat, lon float64, units Units) (Conditions, error)
+}
@@ -0,0 +1,29 @@
+package providers
+
+import "strconv"
+
+// ConvertFromCelsius converts a Celsius temperature into u. UnitsStandard
+// is Kelvin, matching the convention used by OpenWeatherMap; UnitsMetric
+// and any unrecognized value pass tempC through unchanged.
+func (u Units) ConvertFromCelsius(tempC float64) float64 {
+	switch u {
+	case UnitsImperial:
+		return tempC*1.8 + 32
+	case UnitsStandard:
+		return tempC + 273.15
+	default:
+		return tempC
+	}
+}
+
+func parseLatLon(lat, lon string) (float64, float64, error) {
+	latF, err := strconv.ParseFloat(lat, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	lonF, err := strconv.ParseFloat(lon, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return latF, lonF, nil
+}
@@ -0,0 +1,72 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/observability"
+)
+
+// defaultViaCEPBaseURL is the production ViaCEP endpoint.
+const defaultViaCEPBaseURL = "https://viacep.com.br"
+
+// ViaCEP resolves Brazilian CEPs to a city name via the ViaCEP API. It
+// does not return coordinates; pair it with a coordinate-capable
+// fallback geocoder such as Nominatim when lat/lon is required.
+type ViaCEP struct {
+	Client *http.Client
+	// BaseURL overrides the ViaCEP endpoint, defaulting to
+	// defaultViaCEPBaseURL. Tests point it at an httptest.Server so
+	// they run fully offline.
+	BaseURL string
+}
+
+func (v *ViaCEP) Name() string { return "viacep" }
+
+type viaCEPResponse struct {
+	Localidade string `json:"localidade"`
+	Erro       string `json:"erro"`
+}
+
+func (v *ViaCEP) baseURL() string {
+	if v.BaseURL != "" {
+		return v.BaseURL
+	}
+	return defaultViaCEPBaseURL
+}
+
+func (v *ViaCEP) Geocode(ctx context.Context, cep string) (Location, error) {
+	reqURL := fmt.Sprintf("%s/ws/%s/json/", v.baseURL(), cep)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return Location{}, err
+	}
+	observability.Inject(ctx, req)
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return Location{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return Location{}, fmt.Errorf("%w: viacep status %d", ErrUnavailable, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Location{}, fmt.Errorf("viacep status %d", resp.StatusCode)
+	}
+
+	var data viaCEPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return Location{}, fmt.Errorf("viacep: decode response: %w", err)
+	}
+
+	// ViaCEP may return "true" (string) or "1", so we check both.
+	if data.Erro == "true" || data.Erro == "1" || data.Localidade == "" {
+		return Location{}, ErrNotFound
+	}
+
+	return Location{City: data.Localidade}, nil
+}
@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCEPNotFound confirms ViaCEP.Geocode surfaces ErrNotFound when the
+// upstream reports the CEP as unknown, using a mocked ViaCEP server so
+// the test runs fully offline.
+func TestCEPNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"erro":"true"}`)
+	}))
+	defer server.Close()
+
+	v := &ViaCEP{Client: server.Client(), BaseURL: server.URL}
+
+	_, err := v.Geocode(context.Background(), "99999999")
+	if err != ErrNotFound {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
+
+// TestViaCEPGeocodeSuccess confirms a successful ViaCEP lookup resolves
+// to the returned city name.
+func TestViaCEPGeocodeSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"localidade":"São Paulo"}`)
+	}))
+	defer server.Close()
+
+	v := &ViaCEP{Client: server.Client(), BaseURL: server.URL}
+
+	loc, err := v.Geocode(context.Background(), "01001000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loc.City != "São Paulo" {
+		t.Errorf("got city %q, want %q", loc.City, "São Paulo")
+	}
+}
+
+// TestViaCEPGeocodeUnavailable confirms a 5xx from ViaCEP is surfaced as
+// ErrUnavailable so the GeocodingChain fails over instead of giving up.
+func TestViaCEPGeocodeUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	v := &ViaCEP{Client: server.Client(), BaseURL: server.URL}
+
+	_, err := v.Geocode(context.Background(), "01001000")
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got error %v, want %v", err, ErrUnavailable)
+	}
+}
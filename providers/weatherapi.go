@@ -0,0 +1,245 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Guilherme-G-Cadilhe/Go-GoogleCloudRun-Api-Demo/observability"
+)
+
+// defaultWeatherAPIBaseURL is the production weatherapi.com endpoint.
+const defaultWeatherAPIBaseURL = "https://api.weatherapi.com"
+
+// WeatherAPI fetches current conditions from weatherapi.com.
+type WeatherAPI struct {
+	Client *http.Client
+	APIKey string
+	// BaseURL overrides the WeatherAPI endpoint, defaulting to
+	// defaultWeatherAPIBaseURL. Tests point it at an httptest.Server so
+	// they run fully offline.
+	BaseURL string
+}
+
+func (w *WeatherAPI) Name() string { return "weatherapi" }
+
+func (w *WeatherAPI) baseURL() string {
+	if w.BaseURL != "" {
+		return w.BaseURL
+	}
+	return defaultWeatherAPIBaseURL
+}
+
+type weatherAPIResponse struct {
+	Current struct {
+		TempC float64 `json:"temp_c"`
+	} `json:"current"`
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (w *WeatherAPI) CurrentByCity(ctx context.Context, city string, units Units) (Conditions, error) {
+	return w.current(ctx, fmt.Sprintf("q=%s", url.QueryEscape(city)), units)
+}
+
+func (w *WeatherAPI) CurrentByCoordinates(ctx context.Context, lat, lon float64, units Units) (Conditions, error) {
+	return w.current(ctx, fmt.Sprintf("q=%f,%f", lat, lon), units)
+}
+
+func (w *WeatherAPI) current(ctx context.Context, query string, units Units) (Conditions, error) {
+	if w.APIKey == "" {
+		return Conditions{}, fmt.Errorf("weatherapi: API key not configured")
+	}
+
+	reqURL := fmt.Sprintf("%s/v1/current.json?key=%s&%s", w.baseURL(), w.APIKey, query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return Conditions{}, err
+	}
+	observability.Inject(ctx, req)
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return Conditions{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Read best-effort only to detect the one error code (1006, no
+		// matching location) that should map to ErrNotFound instead of
+		// ErrUnavailable; a read failure here doesn't change the outcome.
+		body, _ := io.ReadAll(resp.Body)
+		var apiErr weatherAPIResponse
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Code == 1006 {
+			return Conditions{}, ErrNotFound
+		}
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return Conditions{}, fmt.Errorf("%w: weatherapi status %d", ErrUnavailable, resp.StatusCode)
+		}
+		return Conditions{}, fmt.Errorf("weatherapi status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Conditions{}, fmt.Errorf("weatherapi: read response: %w", err)
+	}
+
+	var data weatherAPIResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return Conditions{}, fmt.Errorf("weatherapi: decode response: %w", err)
+	}
+
+	return Conditions{TempC: data.Current.TempC, Units: units}, nil
+}
+
+type weatherAPIConditionCode struct {
+	Code int `json:"code"`
+}
+
+type weatherAPIForecastResponse struct {
+	Forecast struct {
+		Forecastday []struct {
+			Date string `json:"date"`
+			Day  struct {
+				MaxTempC          float64                 `json:"maxtemp_c"`
+				MinTempC          float64                 `json:"mintemp_c"`
+				DailyChanceOfRain float64                 `json:"daily_chance_of_rain"`
+				MaxWindKPH        float64                 `json:"maxwind_kph"`
+				Condition         weatherAPIConditionCode `json:"condition"`
+			} `json:"day"`
+			Astro struct {
+				Sunrise string `json:"sunrise"`
+				Sunset  string `json:"sunset"`
+			} `json:"astro"`
+			Hour []struct {
+				Time         string                  `json:"time"`
+				TempC        float64                 `json:"temp_c"`
+				ChanceOfRain float64                 `json:"chance_of_rain"`
+				WindKPH      float64                 `json:"wind_kph"`
+				Condition    weatherAPIConditionCode `json:"condition"`
+			} `json:"hour"`
+		} `json:"forecastday"`
+	} `json:"forecast"`
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// mapWeatherAPICondition maps weatherapi.com's numeric condition codes
+// (see https://www.weatherapi.com/docs/weather_conditions.json) onto
+// the provider-independent Condition enum.
+func mapWeatherAPICondition(code int) Condition {
+	switch {
+	case code == 1000:
+		return ConditionClear
+	case code == 1003 || code == 1006 || code == 1009:
+		return ConditionClouds
+	case code == 1030 || code == 1135 || code == 1147:
+		return ConditionFog
+	case code >= 1273 && code <= 1282:
+		return ConditionThunderstorm
+	case code >= 1066 && code <= 1072, code >= 1204 && code <= 1237, code >= 1255 && code <= 1264:
+		return ConditionSnow
+	case code >= 1063 && code <= 1201, code >= 1240 && code <= 1246:
+		return ConditionRain
+	default:
+		return ConditionUnknown
+	}
+}
+
+func (w *WeatherAPI) ForecastByCity(ctx context.Context, city string, days int, units Units) (Forecast, error) {
+	return w.forecast(ctx, fmt.Sprintf("q=%s", url.QueryEscape(city)), days, units)
+}
+
+func (w *WeatherAPI) ForecastByCoordinates(ctx context.Context, lat, lon float64, days int, units Units) (Forecast, error) {
+	return w.forecast(ctx, fmt.Sprintf("q=%f,%f", lat, lon), days, units)
+}
+
+func (w *WeatherAPI) forecast(ctx context.Context, query string, days int, units Units) (Forecast, error) {
+	if w.APIKey == "" {
+		return Forecast{}, fmt.Errorf("weatherapi: API key not configured")
+	}
+
+	reqURL := fmt.Sprintf("%s/v1/forecast.json?key=%s&days=%d&%s", w.baseURL(), w.APIKey, days, query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return Forecast{}, err
+	}
+	observability.Inject(ctx, req)
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return Forecast{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Read best-effort only to detect the one error code (1006, no
+		// matching location) that should map to ErrNotFound instead of
+		// ErrUnavailable; a read failure here doesn't change the outcome.
+		body, _ := io.ReadAll(resp.Body)
+		var apiErr weatherAPIForecastResponse
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Code == 1006 {
+			return Forecast{}, ErrNotFound
+		}
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return Forecast{}, fmt.Errorf("%w: weatherapi status %d", ErrUnavailable, resp.StatusCode)
+		}
+		return Forecast{}, fmt.Errorf("weatherapi status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Forecast{}, fmt.Errorf("weatherapi: read response: %w", err)
+	}
+
+	var data weatherAPIForecastResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		return Forecast{}, fmt.Errorf("weatherapi: decode response: %w", err)
+	}
+
+	out := Forecast{Units: units}
+	for _, day := range data.Forecast.Forecastday {
+		date, err := time.Parse(DateFormat, day.Date)
+		if err != nil {
+			continue
+		}
+
+		sunrise, _ := parseSunTime(date, day.Astro.Sunrise)
+		sunset, _ := parseSunTime(date, day.Astro.Sunset)
+
+		out.Daily = append(out.Daily, DailyForecast{
+			Date:             date,
+			MinTempC:         day.Day.MinTempC,
+			MaxTempC:         day.Day.MaxTempC,
+			PrecipitationPct: day.Day.DailyChanceOfRain,
+			WindKPH:          day.Day.MaxWindKPH,
+			Sunrise:          sunrise,
+			Sunset:           sunset,
+			Condition:        mapWeatherAPICondition(day.Day.Condition.Code),
+		})
+
+		for _, hour := range day.Hour {
+			t, err := time.Parse(hourlyTimeFormat, hour.Time)
+			if err != nil {
+				continue
+			}
+			out.Hourly = append(out.Hourly, HourlyForecast{
+				Time:             t,
+				TempC:            hour.TempC,
+				PrecipitationPct: hour.ChanceOfRain,
+				WindKPH:          hour.WindKPH,
+				Condition:        mapWeatherAPICondition(hour.Condition.Code),
+			})
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,139 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWeatherAPICurrentSuccess confirms a successful WeatherAPI lookup
+// resolves to the returned Celsius temperature.
+func TestWeatherAPICurrentSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"current":{"temp_c":18.2}}`)
+	}))
+	defer server.Close()
+
+	wa := &WeatherAPI{Client: server.Client(), APIKey: "key", BaseURL: server.URL}
+
+	cond, err := wa.CurrentByCity(context.Background(), "São Paulo", UnitsMetric)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cond.TempC != 18.2 {
+		t.Errorf("got TempC %f, want %f", cond.TempC, 18.2)
+	}
+}
+
+// TestWeatherAPICurrentNotFound confirms WeatherAPI's 1006 error code
+// (invalid location) is surfaced as ErrNotFound.
+func TestWeatherAPICurrentNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"code":1006,"message":"No matching location found."}}`)
+	}))
+	defer server.Close()
+
+	wa := &WeatherAPI{Client: server.Client(), APIKey: "key", BaseURL: server.URL}
+
+	_, err := wa.CurrentByCity(context.Background(), "Nowhere", UnitsMetric)
+	if err != ErrNotFound {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
+
+// TestWeatherAPICurrentUnavailable confirms a 5xx from WeatherAPI is
+// surfaced as ErrUnavailable so the WeatherChain fails over instead of
+// giving up.
+func TestWeatherAPICurrentUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "upstream down")
+	}))
+	defer server.Close()
+
+	wa := &WeatherAPI{Client: server.Client(), APIKey: "key", BaseURL: server.URL}
+
+	_, err := wa.CurrentByCity(context.Background(), "São Paulo", UnitsMetric)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got error %v, want %v", err, ErrUnavailable)
+	}
+}
+
+// TestWeatherAPIForecastSuccess confirms a successful forecast response
+// is parsed into daily and hourly points with condition codes mapped
+// onto the provider-independent Condition enum.
+func TestWeatherAPIForecastSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"forecast":{"forecastday":[{
+			"date":"2026-07-27",
+			"day":{"maxtemp_c":25,"mintemp_c":15,"daily_chance_of_rain":40,"maxwind_kph":20,"condition":{"code":1063}},
+			"astro":{"sunrise":"06:12 AM","sunset":"05:43 PM"},
+			"hour":[{"time":"2026-07-27 12:00","temp_c":22,"chance_of_rain":10,"wind_kph":15,"condition":{"code":1000}}]
+		}]}}`)
+	}))
+	defer server.Close()
+
+	wa := &WeatherAPI{Client: server.Client(), APIKey: "key", BaseURL: server.URL}
+
+	forecast, err := wa.ForecastByCity(context.Background(), "São Paulo", 1, UnitsMetric)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(forecast.Daily) != 1 {
+		t.Fatalf("got %d daily points, want 1", len(forecast.Daily))
+	}
+	day := forecast.Daily[0]
+	if day.MaxTempC != 25 || day.MinTempC != 15 {
+		t.Errorf("got temps (%f, %f), want (25, 15)", day.MaxTempC, day.MinTempC)
+	}
+	if day.Condition != ConditionRain {
+		t.Errorf("got condition %v, want %v", day.Condition, ConditionRain)
+	}
+
+	if len(forecast.Hourly) != 1 {
+		t.Fatalf("got %d hourly points, want 1", len(forecast.Hourly))
+	}
+	if forecast.Hourly[0].Condition != ConditionClear {
+		t.Errorf("got hourly condition %v, want %v", forecast.Hourly[0].Condition, ConditionClear)
+	}
+}
+
+// TestWeatherAPIForecastNotFound confirms the forecast endpoint maps the
+// same 1006 error code onto ErrNotFound as the current-conditions
+// endpoint.
+func TestWeatherAPIForecastNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"code":1006,"message":"No matching location found."}}`)
+	}))
+	defer server.Close()
+
+	wa := &WeatherAPI{Client: server.Client(), APIKey: "key", BaseURL: server.URL}
+
+	_, err := wa.ForecastByCity(context.Background(), "Nowhere", 1, UnitsMetric)
+	if err != ErrNotFound {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
+
+// TestWeatherAPIForecastUnavailable confirms a 5xx from the forecast
+// endpoint with an empty body is surfaced as ErrUnavailable (see
+// ErrUnavailable wrapping in forecast).
+func TestWeatherAPIForecastUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	wa := &WeatherAPI{Client: server.Client(), APIKey: "key", BaseURL: server.URL}
+
+	_, err := wa.ForecastByCity(context.Background(), "São Paulo", 1, UnitsMetric)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got error %v, want %v", err, ErrUnavailable)
+	}
+}